@@ -0,0 +1,113 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines to show around each change.
+const diffContext = 3
+
+// diff returns a unified diff between the old and new content of a file, or an
+// empty string if they are identical.
+func diff(name string, old, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+	var (
+		a = splitLines(old)
+		b = splitLines(new)
+	)
+	// Compute the longest common subsequence lengths of all suffix pairs
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	// Walk the table to produce the edit script, one operation per line
+	type edit struct {
+		op   byte // ' ' for kept, '-' for deleted and '+' for inserted lines
+		line string
+		i, j int // Line indices within the old and new content
+	}
+	var edits []edit
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+	// Group the changes into hunks with some surrounding context
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s (generated)\n", name, name)
+
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			start++
+			continue
+		}
+		// Extend the hunk until the next change is too far away
+		end := start
+		for next := start; next < len(edits); next++ {
+			if edits[next].op != ' ' {
+				if next-end > 2*diffContext {
+					break
+				}
+				end = next
+			}
+		}
+		first, last := max(start-diffContext, 0), min(end+diffContext, len(edits)-1)
+
+		var dels, ins int
+		for _, e := range edits[first : last+1] {
+			if e.op != '+' {
+				dels++
+			}
+			if e.op != '-' {
+				ins++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(edits[first].i, dels), hunkRange(edits[first].j, ins))
+		for _, e := range edits[first : last+1] {
+			fmt.Fprintf(&out, "%c%s\n", e.op, e.line)
+		}
+		start = last + 1
+	}
+	return out.String()
+}
+
+// splitLines splits a file into lines, dropping the final newline.
+func splitLines(blob []byte) []string {
+	if len(blob) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(blob), "\n"), "\n")
+}
+
+// hunkRange formats the line range of a unified diff hunk.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,213 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// marker is the comment flagging a Go file as generated.
+const marker = "// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.\n\n"
+
+// generate creates the SizeSSZ and DefineSSZ methods for the requested types
+// declared in the loaded Go package.
+func (g *generator) generate(names []string) ([]byte, error) {
+	var layouts []*container
+	for _, name := range names {
+		layout, err := g.lookup(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, layout)
+	}
+	var code bytes.Buffer
+	if len(layouts) > 0 {
+		code.WriteString(g.header(layouts[0].named))
+	}
+	code.WriteString(marker)
+	fmt.Fprintf(&code, "package %s\n\n", g.pkg.Name())
+	fmt.Fprintf(&code, "import \"github.com/karalabe/ssz\"\n")
+
+	for _, layout := range layouts {
+		recv := g.receiver(layout.named)
+		generateSizer(&code, layout, recv)
+		generateDefiner(&code, layout, recv)
+	}
+	return format.Source(code.Bytes())
+}
+
+// generateSizer creates the SizeSSZ method of a container.
+func generateSizer(code *bytes.Buffer, layout *container, recv string) {
+	name := layout.named.Obj().Name()
+	if !layout.dynamic {
+		fmt.Fprintf(code, "\nfunc (%s *%s) SizeSSZ() uint32 { return %s }\n", recv, name, staticSize(layout))
+		return
+	}
+	fmt.Fprintf(code, "\nfunc (%s *%s) SizeSSZ(fixed bool) uint32 {\n", recv, name)
+	fmt.Fprintf(code, "\tsize := uint32(%s)\n", staticSize(layout))
+	fmt.Fprintf(code, "\tif !fixed {\n")
+
+	var nameWidth int
+	for _, f := range layout.fields {
+		if f.content != "" {
+			nameWidth = max(nameWidth, len(f.name))
+		}
+	}
+	for i, f := range layout.fields {
+		if f.content == "" {
+			continue
+		}
+		comment := fmt.Sprintf("Field (%*d) - %s", digits(layout), i, f.name)
+		if limit := bounds(f); limit != "" {
+			comment = fmt.Sprintf("Field (%*d) - %-*s - %s (not enforced)", digits(layout), i, nameWidth, f.name, limit)
+		}
+		fmt.Fprintf(code, "\t\tsize += ssz.%s(%s) // %s\n", f.sizer, value(recv, f), comment)
+	}
+	fmt.Fprintf(code, "\t}\n")
+	fmt.Fprintf(code, "\treturn size\n")
+	fmt.Fprintf(code, "}\n")
+}
+
+// generateDefiner creates the DefineSSZ method of a container.
+func generateDefiner(code *bytes.Buffer, layout *container, recv string) {
+	name := layout.named.Obj().Name()
+
+	// Calculate the column widths of the trailing field comments
+	var nameWidth, sizeWidth int
+	for _, f := range layout.fields {
+		nameWidth = max(nameWidth, len(f.name))
+		sizeWidth = max(sizeWidth, len(strconv.FormatUint(uint64(f.size), 10)))
+	}
+	kindWidth := len("Field")
+	if layout.dynamic {
+		kindWidth = len("Offset")
+	}
+	comments := make([]string, len(layout.fields))
+	for i, f := range layout.fields {
+		kind := "Field"
+		if f.content != "" {
+			kind = "Offset"
+		}
+		comments[i] = fmt.Sprintf("%-*s (%*d) - %-*s - %*d %s", kindWidth, kind, digits(layout), i, nameWidth, f.name, sizeWidth, f.size, unit(uint64(f.size)))
+	}
+	fmt.Fprintf(code, "func (%s *%s) DefineSSZ(codec *ssz.Codec) {\n", recv, name)
+	for i, f := range layout.fields {
+		args := []string{named(f), reference(recv, f)}
+		for _, extra := range f.extra {
+			args = append(args, number(extra))
+		}
+		fmt.Fprintf(code, "\tssz.%s(%s) // %s\n", f.define, strings.Join(args, ", "), comments[i])
+	}
+	if layout.dynamic {
+		fmt.Fprintf(code, "\n")
+		for i, f := range layout.fields {
+			if f.content == "" {
+				continue
+			}
+			args := []string{"codec", reference(recv, f)}
+			for _, limit := range f.limits {
+				args = append(args, number(limit))
+			}
+			fmt.Fprintf(code, "\tssz.%s(%s) // %s\n", f.content, strings.Join(args, ", "), comments[i])
+		}
+	}
+	fmt.Fprintf(code, "}\n")
+}
+
+// staticSize returns the expression of the static size of a container, summing
+// up the plain field sizes but keeping arrays as item count times item size.
+func staticSize(layout *container) string {
+	var (
+		terms []string
+		sum   uint32
+	)
+	for _, f := range layout.fields {
+		if f.items == 0 {
+			sum += f.size
+			continue
+		}
+		terms = append(terms, fmt.Sprintf("%d*%d", f.items, f.size/f.items))
+	}
+	if sum > 0 || len(terms) == 0 {
+		terms = append(terms, strconv.FormatUint(uint64(sum), 10))
+	}
+	return strings.Join(terms, " + ")
+}
+
+// bounds returns the description of the limits of a dynamic field, or an empty
+// string if it has none.
+func bounds(f *field) string {
+	switch {
+	case len(f.limits) == 0:
+		return ""
+	case f.sizer == "SizeBitlist":
+		return fmt.Sprintf("max %d bits", f.limits[0])
+	case f.sizer == "SizeDynamicBytes":
+		return fmt.Sprintf("max %d bytes", f.limits[0])
+	case len(f.limits) == 2:
+		return fmt.Sprintf("max %d items, %d %s each", f.limits[0], f.limits[1], unit(f.limits[1]))
+	case f.stride != 0:
+		return fmt.Sprintf("max %d items, %d %s each", f.limits[0], f.stride, unit(uint64(f.stride)))
+	default:
+		return fmt.Sprintf("max %d items", f.limits[0])
+	}
+}
+
+// unit returns the byte unit to print after a size.
+func unit(size uint64) string {
+	if size == 1 {
+		return "byte"
+	}
+	return "bytes"
+}
+
+// named returns the codec expression to pass to a field definer.
+func named(f *field) string {
+	if f.ssz == "" {
+		return "codec"
+	}
+	return fmt.Sprintf("codec.Named(%q)", f.ssz)
+}
+
+// reference returns the expression to pass a field to its definers.
+func reference(recv string, f *field) string {
+	if f.array {
+		return recv + "." + f.name + "[:]"
+	}
+	return "&" + recv + "." + f.name
+}
+
+// value returns the expression to pass a field to its sizer.
+func value(recv string, f *field) string {
+	if f.array {
+		return recv + "." + f.name + "[:]"
+	}
+	return recv + "." + f.name
+}
+
+// digits returns the number of digits needed to print the field indices.
+func digits(layout *container) int {
+	return len(strconv.Itoa(max(len(layout.fields)-1, 0)))
+}
+
+// number formats a limit, grouping the digits of large ones for readability.
+func number(n uint64) string {
+	s := strconv.FormatUint(n, 10)
+	if n < 1_000_000 {
+		return s
+	}
+	var out []byte
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, '_')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
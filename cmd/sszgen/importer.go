@@ -0,0 +1,77 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+// sourceImporter is a types.ImporterFrom that type checks the imported packages
+// from source, resolving them via its own build context (instead of the global
+// build.Default that the go/importer source importer is bound to).
+type sourceImporter struct {
+	ctx  *build.Context
+	fset *token.FileSet
+	pkgs map[string]*types.Package // Packages already imported, keyed by directory
+}
+
+// newSourceImporter creates a source importer resolving packages via the given
+// build context.
+func newSourceImporter(ctx *build.Context, fset *token.FileSet) *sourceImporter {
+	return &sourceImporter{
+		ctx:  ctx,
+		fset: fset,
+		pkgs: make(map[string]*types.Package),
+	}
+}
+
+// Import implements types.Importer.
+func (imp *sourceImporter) Import(path string) (*types.Package, error) {
+	return imp.ImportFrom(path, imp.ctx.Dir, 0)
+}
+
+// ImportFrom implements types.ImporterFrom.
+func (imp *sourceImporter) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	bpkg, err := imp.ctx.Import(path, dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if pkg, ok := imp.pkgs[bpkg.Dir]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through package %q", path)
+		}
+		return pkg, nil
+	}
+	imp.pkgs[bpkg.Dir] = nil // recursion guard
+
+	var files []*ast.File
+	for _, name := range bpkg.GoFiles {
+		file, err := parser.ParseFile(imp.fset, filepath.Join(bpkg.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	// Only the exported API of the dependencies is needed, so errors in function
+	// bodies (or anything cgo related) are irrelevant.
+	config := types.Config{
+		Importer:         imp,
+		IgnoreFuncBodies: true,
+		FakeImportC:      true,
+		Error:            func(err error) {},
+	}
+	pkg, _ := config.Check(bpkg.ImportPath, imp.fset, files, nil)
+	imp.pkgs[bpkg.Dir] = pkg
+	return pkg, nil
+}
@@ -0,0 +1,88 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// sszgen is a code generator that produces the SizeSSZ and DefineSSZ methods of
+// Go struct types, based on their field types and ssz struct tags.
+//
+// Usage:
+//
+//	sszgen -type Withdrawal,ExecutionPayload [-dir .] [-out gen_ssz.go] [-check]
+//
+// With -check, the output file is not written, rather compared against freshly
+// generated code. Any difference is printed as a diff and sszgen exits with a
+// non-zero status, catching structs that changed since their last generation.
+//
+// The tool is meant to be invoked via go:generate from the package directory:
+//
+//	//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Withdrawal -out gen_withdrawal_ssz.go
+//
+// The generated file starts with the header comments (e.g. license) of the file
+// declaring the first type, and reuses the receiver names of methods already
+// declared on the types.
+//
+// The following struct tags are understood:
+//
+//	json:"name"     name of the field passed to codec.Named
+//	ssz-size:"N"    size of a static field in bytes (bits for bitvectors)
+//	ssz-max:"N,M"   max items of a dynamic field (and max bytes of each item)
+//	ssz:"bits"      treat a byte array or slice as a bitvector or bitlist
+//	ssz:"-"         ignore the field
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	var (
+		dir   = flag.String("dir", ".", "Package directory containing the types")
+		kinds = flag.String("type", "", "Comma separated list of types to generate")
+		out   = flag.String("out", "", "Output file to write (default stdout)")
+		check = flag.Bool("check", false, "Check that the output file is up to date instead of writing it")
+	)
+	flag.Parse()
+
+	if *kinds == "" {
+		fmt.Fprintln(os.Stderr, "sszgen: no types specified (-type)")
+		os.Exit(2)
+	}
+	gen, err := loadPackage(*dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sszgen:", err)
+		os.Exit(1)
+	}
+	code, err := gen.generate(strings.Split(*kinds, ","))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sszgen:", err)
+		os.Exit(1)
+	}
+	if *check {
+		if *out == "" {
+			fmt.Fprintln(os.Stderr, "sszgen: no output file to check (-out)")
+			os.Exit(2)
+		}
+		old, err := os.ReadFile(*out)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, "sszgen:", err)
+			os.Exit(1)
+		}
+		if d := diff(*out, old, code); d != "" {
+			fmt.Print(d)
+			fmt.Fprintf(os.Stderr, "sszgen: %s is out of date, rerun go generate\n", *out)
+			os.Exit(1)
+		}
+		return
+	}
+	if *out == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	if err := os.WriteFile(*out, code, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "sszgen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,202 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// testTypesDir is the package containing the generated consensus spec types.
+var testTypesDir = filepath.Join("..", "..", "tests", "testtypes", "consensus-spec-tests")
+
+// Tests that the generator produces byte-for-byte the same code as the consensus
+// spec types used to have written by hand, kept in testdata (with the typos in
+// their layout comments fixed). Only the generated file marker is missing from
+// the hand-written files and the type declarations from the generated ones, the
+// rest (header, receivers, comments) must match exactly.
+func TestGenerateHandWritten(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.go"))
+	if err != nil {
+		t.Fatalf("failed to list hand-written files: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no hand-written files found")
+	}
+	gen, err := loadPackage(testTypesDir)
+	if err != nil {
+		t.Fatalf("failed to load test types: %v", err)
+	}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		kinds, header, methods, err := split(src)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", file, err)
+		}
+		code, err := gen.generate(kinds)
+		if err != nil {
+			t.Errorf("%s: failed to generate %v: %v", file, kinds, err)
+			continue
+		}
+		if !bytes.Contains(code, []byte(marker)) {
+			t.Errorf("%s: generated code not marked as such", file)
+		}
+		_, genHeader, genMethods, err := split(bytes.Replace(code, []byte(marker), nil, 1))
+		if err != nil {
+			t.Fatalf("%s: failed to parse generated code: %v", file, err)
+		}
+		if d := diff(file, header, genHeader); d != "" {
+			t.Errorf("generated header mismatch:\n%s", d)
+		}
+		if d := diff(file, methods, genMethods); d != "" {
+			t.Errorf("generated code mismatch:\n%s", d)
+		}
+	}
+}
+
+// split parses a Go source file and returns the types that have a DefineSSZ
+// method declared, the source code preceding the package clause and the source
+// code from the first SizeSSZ or DefineSSZ method onward.
+func split(src []byte) (kinds []string, header []byte, methods []byte, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	header = src[:fset.Position(file.Package).Offset]
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || (fn.Name.Name != "SizeSSZ" && fn.Name.Name != "DefineSSZ") {
+			continue
+		}
+		if methods == nil {
+			methods = src[fset.Position(fn.Pos()).Offset:]
+		}
+		if fn.Name.Name == "DefineSSZ" {
+			kinds = append(kinds, fn.Recv.List[0].Type.(*ast.StarExpr).X.(*ast.Ident).Name)
+		}
+	}
+	return kinds, header, methods, nil
+}
+
+// Tests that regenerating the consensus spec test types produces byte-for-byte
+// the same code as checked into the repository, i.e. nobody forgot to rerun
+// go generate after changing the generator or the types.
+func TestGeneratedUpToDate(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(testTypesDir, "gen_*_ssz.go"))
+	if err != nil {
+		t.Fatalf("failed to list generated files: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no generated files found")
+	}
+	gen, err := loadPackage(testTypesDir)
+	if err != nil {
+		t.Fatalf("failed to load test types: %v", err)
+	}
+	definer := regexp.MustCompile(`func \(\w+ \*(\w+)\) DefineSSZ`)
+	for _, file := range files {
+		want, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		var kinds []string
+		for _, match := range definer.FindAllSubmatch(want, -1) {
+			kinds = append(kinds, string(match[1]))
+		}
+		have, err := gen.generate(kinds)
+		if err != nil {
+			t.Errorf("%s: failed to generate %v: %v", file, kinds, err)
+			continue
+		}
+		if d := diff(file, want, have); d != "" {
+			t.Errorf("generated code mismatch:\n%s", d)
+		}
+	}
+}
+
+// Tests that loading a package does not modify the global build context, which
+// would leak the package directory into anything else resolving packages.
+func TestLoadPackageIsolated(t *testing.T) {
+	dir := build.Default.Dir
+	if _, err := loadPackage(testTypesDir); err != nil {
+		t.Fatalf("failed to load test types: %v", err)
+	}
+	if build.Default.Dir != dir {
+		t.Errorf("global build context modified: have dir %q, want %q", build.Default.Dir, dir)
+	}
+}
+
+// Tests that the blob sizes accepted by the generator are the same as the ones
+// accepted by the staticBinary constraint of the ssz library.
+func TestBlobSizes(t *testing.T) {
+	gen, err := loadPackage(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("failed to load ssz package: %v", err)
+	}
+	obj, ok := gen.pkg.Scope().Lookup("staticBinary").(*types.TypeName)
+	if !ok {
+		t.Fatalf("staticBinary constraint not found")
+	}
+	var (
+		iface = obj.Type().Underlying().(*types.Interface)
+		sizes []int64
+	)
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		union, ok := iface.EmbeddedType(i).(*types.Union)
+		if !ok {
+			continue
+		}
+		for j := 0; j < union.Len(); j++ {
+			if array, ok := union.Term(j).Type().(*types.Array); ok {
+				sizes = append(sizes, array.Len())
+			}
+		}
+	}
+	if !slices.Equal(sizes, blobSizes) {
+		t.Errorf("blob sizes mismatch: have %v, ssz library %v", blobSizes, sizes)
+	}
+}
+
+// Tests that drift diffs only contain the changed lines and their context.
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		old  string
+		new  string
+		diff string
+	}{
+		{"a\nb\n", "a\nb\n", ""},
+		{"", "a\n", "--- f\n+++ f (generated)\n@@ -0,0 +1,1 @@\n+a\n"},
+		{"a\n", "", "--- f\n+++ f (generated)\n@@ -1,1 +0,0 @@\n-a\n"},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			"1\n2\n3\n4\nX\n6\n7\n8\n9\n",
+			"--- f\n+++ f (generated)\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+X\n 6\n 7\n 8\n",
+		},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"X\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			"--- f\n+++ f (generated)\n@@ -1,4 +1,4 @@\n-1\n+X\n 2\n 3\n 4\n@@ -7,4 +7,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+	}
+	for i, tt := range tests {
+		if d := diff("f", []byte(tt.old), []byte(tt.new)); d != tt.diff {
+			t.Errorf("test %d: diff mismatch:\nhave:\n%s\nwant:\n%s", i, d, tt.diff)
+		}
+	}
+}
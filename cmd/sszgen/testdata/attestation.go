@@ -0,0 +1,28 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+type Attestation struct {
+	AggregationBits []byte
+	Data            *AttestationData
+	Signature       [96]byte
+}
+
+func (a *Attestation) SizeSSZ(fixed bool) uint32 {
+	size := uint32(228)
+	if !fixed {
+		size += ssz.SizeBitlist(a.AggregationBits) // Field (0) - AggregationBits - max 2048 bits (not enforced)
+	}
+	return size
+}
+func (a *Attestation) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineBitlistOffset(codec.Named("aggregation_bits"), &a.AggregationBits) // Offset (0) - AggregationBits -   4 bytes
+	ssz.DefineStaticObject(codec.Named("data"), &a.Data)                         // Field  (1) - Data            - 128 bytes
+	ssz.DefineStaticBytes(codec.Named("signature"), a.Signature[:])              // Field  (2) - Signature       -  96 bytes
+
+	ssz.DefineBitlistContent(codec, &a.AggregationBits, 2048) // Offset (0) - AggregationBits -   4 bytes
+}
@@ -0,0 +1,24 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+type AttestationData struct {
+	Slot            Slot
+	Index           uint64
+	BeaconBlockHash Hash
+	Source          *Checkpoint
+	Target          *Checkpoint
+}
+
+func (a *AttestationData) SizeSSZ() uint32 { return 128 }
+func (a *AttestationData) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("slot"), &a.Slot)                                // Field (0) - Slot            -  8 bytes
+	ssz.DefineUint64(codec.Named("index"), &a.Index)                              // Field (1) - Index           -  8 bytes
+	ssz.DefineStaticBytes(codec.Named("beacon_block_root"), a.BeaconBlockHash[:]) // Field (2) - BeaconBlockHash - 32 bytes
+	ssz.DefineStaticObject(codec.Named("source"), &a.Source)                      // Field (3) - Source          - 40 bytes
+	ssz.DefineStaticObject(codec.Named("target"), &a.Target)                      // Field (4) - Target          - 40 bytes
+}
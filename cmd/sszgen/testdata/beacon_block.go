@@ -0,0 +1,32 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+type BeaconBlock struct {
+	Slot          Slot
+	ProposerIndex uint64
+	ParentRoot    Hash
+	StateRoot     Hash
+	Body          *BeaconBlockBody
+}
+
+func (b *BeaconBlock) SizeSSZ(fixed bool) uint32 {
+	size := uint32(84)
+	if !fixed {
+		size += ssz.SizeDynamicObject(b.Body) // Field (4) - Body
+	}
+	return size
+}
+func (b *BeaconBlock) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("slot"), &b.Slot)                     // Field  (0) - Slot          -  8 bytes
+	ssz.DefineUint64(codec.Named("proposer_index"), &b.ProposerIndex)  // Field  (1) - ProposerIndex -  8 bytes
+	ssz.DefineStaticBytes(codec.Named("parent_root"), b.ParentRoot[:]) // Field  (2) - ParentRoot    - 32 bytes
+	ssz.DefineStaticBytes(codec.Named("state_root"), b.StateRoot[:])   // Field  (3) - StateRoot     - 32 bytes
+	ssz.DefineDynamicObjectOffset(codec.Named("body"), &b.Body)        // Offset (4) - Body          -  4 bytes
+
+	ssz.DefineDynamicObjectContent(codec, &b.Body) // Offset (4) - Body          -  4 bytes
+}
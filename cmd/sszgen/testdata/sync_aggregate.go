@@ -0,0 +1,18 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+type SyncAggregate struct {
+	SyncCommitteeBits      [64]byte
+	SyncCommitteeSignature [96]byte
+}
+
+func (a *SyncAggregate) SizeSSZ() uint32 { return 160 }
+func (a *SyncAggregate) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineBitvector(codec.Named("sync_committee_bits"), a.SyncCommitteeBits[:], 512)        // Field (0) - SyncCommitteeBits      - 64 bytes
+	ssz.DefineStaticBytes(codec.Named("sync_committee_signature"), a.SyncCommitteeSignature[:]) // Field (1) - SyncCommitteeSignature - 96 bytes
+}
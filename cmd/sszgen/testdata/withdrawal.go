@@ -0,0 +1,22 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+type Withdrawal struct {
+	Index     uint64  `ssz-size:"8"`
+	Validator uint64  `ssz-size:"8"`
+	Address   Address `ssz-size:"20"`
+	Amount    uint64  `ssz-size:"8"`
+}
+
+func (w *Withdrawal) SizeSSZ() uint32 { return 44 }
+func (w *Withdrawal) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("index"), &w.Index)               // Field (0) - Index     -  8 bytes
+	ssz.DefineUint64(codec.Named("validator_index"), &w.Validator) // Field (1) - Validator -  8 bytes
+	ssz.DefineStaticBytes(codec.Named("address"), w.Address[:])    // Field (2) - Address   - 20 bytes
+	ssz.DefineUint64(codec.Named("amount"), &w.Amount)             // Field (3) - Amount    -  8 bytes
+}
@@ -0,0 +1,405 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// container is the ssz layout of a Go struct type.
+type container struct {
+	named   *types.Named
+	fields  []*field
+	size    uint32 // Size of the static section (fields and offsets)
+	dynamic bool   // Whether the container has any dynamic fields
+}
+
+// field is the ssz layout of a single struct field.
+type field struct {
+	name    string   // Name of the Go struct field
+	ssz     string   // Name of the field passed to codec.Named (empty if none)
+	size    uint32   // Size of the field in the static section
+	array   bool     // Whether the field is referenced as an array slice
+	items   uint32   // Number of items in an array of multi-byte items (0 otherwise)
+	stride  uint32   // Size of the items in a list of static items (0 otherwise)
+	define  string   // Definer of the static field or the dynamic offset
+	extra   []uint64 // Extra arguments passed to the static definer
+	content string   // Definer of the dynamic content (empty if static)
+	sizer   string   // Sizer of the dynamic content (empty if static)
+	limits  []uint64 // Limits passed to the dynamic content definer
+}
+
+// generator collects the ssz layouts of the types within a package.
+type generator struct {
+	pkg    *types.Package
+	files  []*ast.File
+	errs   []error                     // Type checking errors
+	layout map[*types.Named]*container // Layouts already resolved
+	active map[*types.Named]struct{}   // Layouts being resolved (recursion guard)
+}
+
+// loadPackage parses and type checks the Go package in the given directory.
+func loadPackage(dir string) (*generator, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve modules relative to the package, and avoid cgo so that pure Go
+	// variants of any dependencies are type checked
+	ctx := build.Default
+	ctx.Dir = dir
+	ctx.CgoEnabled = false
+
+	bpkg, err := ctx.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		fset  = token.NewFileSet()
+		files []*ast.File
+	)
+	for _, name := range bpkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	gen := &generator{
+		files:  files,
+		layout: make(map[*types.Named]*container),
+		active: make(map[*types.Named]struct{}),
+	}
+	// Previously generated methods might be stale, so type errors are collected
+	// but otherwise ignored, only used to explain unresolvable field types.
+	config := types.Config{
+		Importer: newSourceImporter(&ctx, fset),
+		Error:    func(err error) { gen.errs = append(gen.errs, err) },
+	}
+	gen.pkg, _ = config.Check(bpkg.ImportPath, fset, files, nil)
+	return gen, nil
+}
+
+// header returns the comments preceding the package clause of the file a type
+// is declared in (e.g. a license), skipping the package documentation.
+func (g *generator) header(named *types.Named) string {
+	for _, file := range g.files {
+		if file.FileStart > named.Obj().Pos() || named.Obj().Pos() >= file.FileEnd {
+			continue
+		}
+		var header strings.Builder
+		for _, group := range file.Comments {
+			if group.Pos() >= file.Package || group == file.Doc {
+				break
+			}
+			for _, comment := range group.List {
+				header.WriteString(comment.Text + "\n")
+			}
+			header.WriteString("\n")
+		}
+		return header.String()
+	}
+	return ""
+}
+
+// receiver returns the receiver name used by the methods already declared on a
+// type (e.g. previously generated ones), or its lowercase initial if none.
+func (g *generator) receiver(named *types.Named) string {
+	for i := 0; i < named.NumMethods(); i++ {
+		recv := named.Method(i).Type().(*types.Signature).Recv()
+		if name := recv.Name(); name != "" && name != "_" {
+			return name
+		}
+	}
+	return string(unicode.ToLower([]rune(named.Obj().Name())[0]))
+}
+
+// lookup retrieves the ssz layout of a struct type declared in the package.
+func (g *generator) lookup(name string) (*container, error) {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in package %s", name, g.pkg.Name())
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("type %s is an alias", name)
+	}
+	return g.container(named)
+}
+
+// container resolves the ssz layout of a named struct type.
+func (g *generator) container(named *types.Named) (*container, error) {
+	if layout, ok := g.layout[named]; ok {
+		return layout, nil
+	}
+	if _, ok := g.active[named]; ok {
+		return nil, fmt.Errorf("type %s is recursive", named.Obj().Name())
+	}
+	g.active[named] = struct{}{}
+	defer delete(g.active, named)
+
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", named.Obj().Name())
+	}
+	layout := &container{named: named}
+	for i := 0; i < st.NumFields(); i++ {
+		f, err := g.field(st.Field(i), reflect.StructTag(st.Tag(i)))
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", named.Obj().Name(), st.Field(i).Name(), err)
+		}
+		if f == nil {
+			continue
+		}
+		layout.fields = append(layout.fields, f)
+		layout.size += f.size
+		if f.content != "" {
+			layout.dynamic = true
+		}
+	}
+	g.layout[named] = layout
+	return layout, nil
+}
+
+// field resolves the ssz layout of a single struct field, returning nil if the
+// field is ignored.
+func (g *generator) field(v *types.Var, tag reflect.StructTag) (*field, error) {
+	var bits bool
+	switch kind := tag.Get("ssz"); kind {
+	case "-":
+		return nil, nil
+	case "bits":
+		bits = true
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown ssz tag %q", kind)
+	}
+	if v.Embedded() {
+		return nil, errors.New("embedded fields are not supported")
+	}
+	size, err := parseTag(tag, "ssz-size")
+	if err != nil {
+		return nil, err
+	}
+	limits, err := parseTag(tag, "ssz-max")
+	if err != nil {
+		return nil, err
+	}
+	f, err := g.resolve(v.Type(), bits, size)
+	if err != nil {
+		return nil, err
+	}
+	f.name = v.Name()
+	if name, _, _ := strings.Cut(tag.Get("json"), ","); name != "-" {
+		f.ssz = name
+	}
+	// Cross check the tags against the resolved layout
+	if f.content == "" {
+		if len(limits) != 0 {
+			return nil, errors.New("ssz-max tag on static field")
+		}
+		if len(size) == 1 && !bits && uint64(f.size) != size[0] {
+			return nil, fmt.Errorf("ssz-size tag %d mismatches type size %d", size[0], f.size)
+		}
+	} else {
+		if len(size) != 0 {
+			return nil, errors.New("ssz-size tag on dynamic field")
+		}
+		if len(limits) != len(f.limits) {
+			return nil, fmt.Errorf("ssz-max tag requires %d limit(s), have %d", len(f.limits), len(limits))
+		}
+		f.limits = limits
+	}
+	return f, nil
+}
+
+// resolve maps a Go type onto its ssz definers.
+func (g *generator) resolve(typ types.Type, bits bool, size []uint64) (*field, error) {
+	if bits {
+		switch t := typ.Underlying().(type) {
+		case *types.Array:
+			if isByte(t.Elem()) {
+				nbits := uint64(t.Len()) * 8
+				if len(size) == 1 {
+					if size[0] > nbits || size[0] <= nbits-8 {
+						return nil, fmt.Errorf("ssz-size tag %d bits mismatches %d byte array", size[0], t.Len())
+					}
+					nbits = size[0]
+				}
+				return &field{size: uint32(t.Len()), array: true, define: "DefineBitvector", extra: []uint64{nbits}}, nil
+			}
+		case *types.Slice:
+			if isByte(t.Elem()) {
+				return &field{size: 4, define: "DefineBitlistOffset", content: "DefineBitlistContent", sizer: "SizeBitlist", limits: make([]uint64, 1)}, nil
+			}
+		}
+		return nil, fmt.Errorf("ssz bits tag on unsupported type %s", typ)
+	}
+	if isUint256(typ) {
+		return nil, fmt.Errorf("unsupported type %s (use a pointer)", typ)
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Bool:
+			return &field{size: 1, define: "DefineBool"}, nil
+		case types.Uint8:
+			return &field{size: 1, define: "DefineUint8"}, nil
+		case types.Uint16:
+			return &field{size: 2, define: "DefineUint16"}, nil
+		case types.Uint32:
+			return &field{size: 4, define: "DefineUint32"}, nil
+		case types.Uint64:
+			return &field{size: 8, define: "DefineUint64"}, nil
+		}
+	case *types.Pointer:
+		if isUint256(t.Elem()) {
+			return &field{size: 32, define: "DefineUint256"}, nil
+		}
+		obj, err := g.object(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if obj.dynamic {
+			return &field{size: 4, define: "DefineDynamicObjectOffset", content: "DefineDynamicObjectContent", sizer: "SizeDynamicObject"}, nil
+		}
+		return &field{size: obj.size, define: "DefineStaticObject"}, nil
+
+	case *types.Array:
+		n := uint32(t.Len())
+		if isByte(t.Elem()) {
+			return &field{size: n, array: true, define: "DefineStaticBytes"}, nil
+		}
+		switch e := t.Elem().Underlying().(type) {
+		case *types.Basic:
+			switch e.Kind() {
+			case types.Bool:
+				return &field{size: n, array: true, define: "DefineArrayOfBools"}, nil
+			case types.Uint64:
+				return &field{size: n * 8, array: true, items: n, define: "DefineArrayOfUint64s"}, nil
+			}
+		case *types.Array:
+			if isByte(e.Elem()) {
+				if !isBlobSize(e.Len()) {
+					return nil, fmt.Errorf("unsupported blob size %d in %s", e.Len(), typ)
+				}
+				return &field{size: n * uint32(e.Len()), array: true, items: n, define: "DefineArrayOfStaticBytes"}, nil
+			}
+		case *types.Pointer:
+			obj, err := g.object(e.Elem())
+			if err != nil {
+				return nil, err
+			}
+			if obj.dynamic {
+				return &field{size: 4, array: true, define: "DefineArrayOfDynamicObjectsOffset", content: "DefineArrayOfDynamicObjectsContent", sizer: "SizeSliceOfDynamicObjects"}, nil
+			}
+			return &field{size: n * obj.size, array: true, items: n, define: "DefineArrayOfStaticObjects"}, nil
+		}
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &field{size: 4, define: "DefineDynamicBytesOffset", content: "DefineDynamicBytesContent", sizer: "SizeDynamicBytes", limits: make([]uint64, 1)}, nil
+		}
+		switch e := t.Elem().Underlying().(type) {
+		case *types.Basic:
+			switch e.Kind() {
+			case types.Bool:
+				return &field{size: 4, stride: 1, define: "DefineSliceOfBoolsOffset", content: "DefineSliceOfBoolsContent", sizer: "SizeSliceOfBools", limits: make([]uint64, 1)}, nil
+			case types.Uint64:
+				return &field{size: 4, stride: 8, define: "DefineSliceOfUint64sOffset", content: "DefineSliceOfUint64sContent", sizer: "SizeSliceOfUint64s", limits: make([]uint64, 1)}, nil
+			}
+		case *types.Array:
+			if isByte(e.Elem()) {
+				if !isBlobSize(e.Len()) {
+					return nil, fmt.Errorf("unsupported blob size %d in %s", e.Len(), typ)
+				}
+				return &field{size: 4, stride: uint32(e.Len()), define: "DefineSliceOfStaticBytesOffset", content: "DefineSliceOfStaticBytesContent", sizer: "SizeSliceOfStaticBytes", limits: make([]uint64, 1)}, nil
+			}
+		case *types.Slice:
+			if isByte(e.Elem()) {
+				return &field{size: 4, define: "DefineSliceOfDynamicBytesOffset", content: "DefineSliceOfDynamicBytesContent", sizer: "SizeSliceOfDynamicBytes", limits: make([]uint64, 2)}, nil
+			}
+		case *types.Pointer:
+			obj, err := g.object(e.Elem())
+			if err != nil {
+				return nil, err
+			}
+			if obj.dynamic {
+				return &field{size: 4, define: "DefineSliceOfDynamicObjectsOffset", content: "DefineSliceOfDynamicObjectsContent", sizer: "SizeSliceOfDynamicObjects", limits: make([]uint64, 1)}, nil
+			}
+			return &field{size: 4, stride: obj.size, define: "DefineSliceOfStaticObjectsOffset", content: "DefineSliceOfStaticObjectsContent", sizer: "SizeSliceOfStaticObjects", limits: make([]uint64, 1)}, nil
+		}
+	}
+	if typ.Underlying() == types.Typ[types.Invalid] && len(g.errs) > 0 {
+		return nil, fmt.Errorf("unresolved type: %v", g.errs[0])
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// object resolves the ssz layout of a type referenced via a pointer.
+func (g *generator) object(typ types.Type) (*container, error) {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type *%s", typ)
+	}
+	return g.container(named)
+}
+
+// parseTag parses a comma separated list of numbers from a struct tag.
+func parseTag(tag reflect.StructTag, key string) ([]uint64, error) {
+	val, ok := tag.Lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	var nums []uint64
+	for _, part := range strings.Split(val, ",") {
+		num, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s tag %q", key, val)
+		}
+		nums = append(nums, num)
+	}
+	return nums, nil
+}
+
+// isByte reports whether a type is a byte (or a named type derived from it).
+func isByte(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Uint8
+}
+
+// blobSizes are the byte array sizes that the ssz library accepts as items of
+// vectors and lists of static binary blobs (its staticBinary constraint).
+var blobSizes = []int64{4, 20, 32, 48, 96, 256}
+
+// isBlobSize reports whether the ssz library accepts byte arrays of the given size
+// as items of vectors and lists of static binary blobs.
+func isBlobSize(size int64) bool {
+	for _, blob := range blobSizes {
+		if blob == size {
+			return true
+		}
+	}
+	return false
+}
+
+// isUint256 reports whether a type is github.com/holiman/uint256.Int.
+func isUint256(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "github.com/holiman/uint256" && named.Obj().Name() == "Int"
+}
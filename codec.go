@@ -4,14 +4,23 @@
 
 package ssz
 
-import "github.com/holiman/uint256"
+import (
+	"fmt"
 
-// Codec is a unified SSZ encoder and decoder that allows simple structs to
-// define their schemas once and have that work for both operations at once
+	"github.com/holiman/uint256"
+)
+
+// Codec is a unified SSZ encoder, decoder and hasher that allows simple structs
+// to define their schemas once and have that work for all operations at once
 // (with the same speed as explicitly typing them out would, of course).
 type Codec struct {
 	enc *Encoder
 	dec *Decoder
+	has *Hasher
+	siz *sizer
+
+	stable     stableState // Layout of the StableContainer or Profile being defined
+	stableBits []byte      // Scratch space for the active and optional bitvectors
 }
 
 // DefineEncoder uses a dedicated encoder in case the types SSZ conversion is for
@@ -19,10 +28,18 @@ type Codec struct {
 // outer context).
 //
 // In reality, it will be the live code run when the object is being serialized.
+// Asymmetric objects must also define a dedicated hasher via DefineHasher, else
+// hashing them panics.
 func (c *Codec) DefineEncoder(impl func(enc *Encoder)) {
 	if c.enc != nil {
 		impl(c.enc)
 	}
+	if c.siz != nil {
+		c.siz.opaque = true
+	}
+	if c.has != nil {
+		c.has.layers[len(c.has.layers)-1].opaque = true
+	}
 }
 
 // DefineDecoder uses a dedicated decoder in case the types SSZ conversion is for
@@ -30,10 +47,126 @@ func (c *Codec) DefineEncoder(impl func(enc *Encoder)) {
 // outer context).
 //
 // In reality, it will be the live code run when the object is being parsed.
+// Asymmetric objects must also define a dedicated hasher via DefineHasher, else
+// hashing them panics.
 func (c *Codec) DefineDecoder(impl func(dec *Decoder)) {
 	if c.dec != nil {
 		impl(c.dec)
 	}
+	if c.siz != nil {
+		c.siz.opaque = true
+	}
+	if c.has != nil {
+		c.has.layers[len(c.has.layers)-1].opaque = true
+	}
+}
+
+// DefineHasher uses a dedicated hasher in case the types SSZ conversion is for
+// some reason asymmetric (e.g. encoding depends on fields, decoding depends on
+// outer context).
+//
+// In reality, it will be the live code run when the object is being hashed.
+func (c *Codec) DefineHasher(impl func(has *Hasher)) {
+	if c.has != nil {
+		c.has.layers[len(c.has.layers)-1].hashed = true
+		impl(c.has)
+	}
+	if c.siz != nil {
+		c.siz.opaque = true
+	}
+}
+
+// Named attaches a name to the next field to be defined, returning the codec
+// itself so it can be inlined into the field definition. Names are optional and
+// are only used to resolve field paths into generalized indices and to report
+// size mismatches and decoding failures; they do not influence encoding, decoding
+// or hashing.
+//
+// Note, dynamic fields should be named at their offset definition, the content
+// definition will inherit it.
+func (c *Codec) Named(name string) *Codec {
+	if c.has != nil && c.has.tree {
+		c.has.names = append(c.has.names, hasherName{
+			layer: len(c.has.layers) - 1,
+			chunk: len(c.has.chunks),
+			name:  name,
+		})
+	}
+	if c.dec != nil && c.dec.err == nil && !c.stable.collect {
+		c.dec.next, c.dec.mark = name, c.stable
+	}
+	if c.siz != nil && c.siz.check {
+		c.siz.name = name
+	}
+	return c
+}
+
+// DefineBool defines the next field as a 1 byte boolean.
+func DefineBool[T ~bool](c *Codec, v *T) {
+	if c.enc != nil {
+		EncodeBool(c.enc, *v)
+		return
+	}
+	if c.dec != nil {
+		DecodeBool(c.dec, v)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(1)
+		return
+	}
+	HashBool(c.has, *v)
+}
+
+// DefineUint8 defines the next field as a uint8.
+func DefineUint8[T ~uint8](c *Codec, n *T) {
+	if c.enc != nil {
+		EncodeUint8(c.enc, *n)
+		return
+	}
+	if c.dec != nil {
+		DecodeUint8(c.dec, n)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(1)
+		return
+	}
+	HashUint8(c.has, *n)
+}
+
+// DefineUint16 defines the next field as a uint16.
+func DefineUint16[T ~uint16](c *Codec, n *T) {
+	if c.enc != nil {
+		EncodeUint16(c.enc, *n)
+		return
+	}
+	if c.dec != nil {
+		DecodeUint16(c.dec, n)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(2)
+		return
+	}
+	HashUint16(c.has, *n)
+}
+
+// DefineUint32 defines the next field as a uint32.
+func DefineUint32[T ~uint32](c *Codec, n *T) {
+	if c.enc != nil {
+		EncodeUint32(c.enc, *n)
+		return
+	}
+	if c.dec != nil {
+		DecodeUint32(c.dec, n)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(4)
+		return
+	}
+	HashUint32(c.has, *n)
 }
 
 // DefineUint64 defines the next field as a uint64.
@@ -42,7 +175,34 @@ func DefineUint64[T ~uint64](c *Codec, n *T) {
 		EncodeUint64(c.enc, *n)
 		return
 	}
-	DecodeUint64(c.dec, n)
+	if c.dec != nil {
+		DecodeUint64(c.dec, n)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(8)
+		return
+	}
+	HashUint64(c.has, *n)
+}
+
+// DefineUint128 defines the next field as a uint128, represented as two uint64
+// limbs in little endian order (least significant first). This avoids needing a
+// big integer library for the occasional 128 bit field.
+func DefineUint128[T ~[2]uint64](c *Codec, n *T) {
+	if c.enc != nil {
+		EncodeUint128(c.enc, *n)
+		return
+	}
+	if c.dec != nil {
+		DecodeUint128(c.dec, n)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(16)
+		return
+	}
+	HashUint128(c.has, *n)
 }
 
 // DefineUint256 defines the next field as a uint256.
@@ -51,7 +211,15 @@ func DefineUint256(c *Codec, n **uint256.Int) {
 		EncodeUint256(c.enc, *n)
 		return
 	}
-	DecodeUint256(c.dec, n)
+	if c.dec != nil {
+		DecodeUint256(c.dec, n)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(32)
+		return
+	}
+	HashUint256(c.has, *n)
 }
 
 // DefineStaticBytes defines the next field as static binary blob.
@@ -60,7 +228,39 @@ func DefineStaticBytes(c *Codec, bytes []byte) {
 		EncodeStaticBytes(c.enc, bytes)
 		return
 	}
-	DecodeStaticBytes(c.dec, bytes)
+	if c.dec != nil {
+		DecodeStaticBytes(c.dec, bytes)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(uint32(len(bytes)))
+		return
+	}
+	HashStaticBytes(c.has, bytes)
+}
+
+// DefineBitvector defines the next field as a static bitvector of the given size
+// (in bits), backed by a byte slice of (size+7)/8 bytes.
+//
+// Backing the bitvector with a differently sized slice is a programming error the
+// compiler cannot catch, so it is rejected with a panic.
+func DefineBitvector(c *Codec, bits []byte, size uint64) {
+	if want := (size + 7) / 8; uint64(len(bits)) != want {
+		panic(fmt.Sprintf("invalid bitvector: %d bits backed by %d bytes, want %d", size, len(bits), want))
+	}
+	if c.enc != nil {
+		EncodeBitvector(c.enc, bits, size)
+		return
+	}
+	if c.dec != nil {
+		DecodeBitvector(c.dec, bits, size)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(uint32(len(bits)))
+		return
+	}
+	HashBitvector(c.has, bits)
 }
 
 // DefineDynamicBytesOffset defines the next field as dynamic binary blob.
@@ -69,7 +269,15 @@ func DefineDynamicBytesOffset(c *Codec, blob *[]byte) {
 		EncodeDynamicBytesOffset(c.enc, *blob)
 		return
 	}
-	DecodeDynamicBytesOffset(c.dec, blob)
+	if c.dec != nil {
+		DecodeDynamicBytesOffset(c.dec, blob)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashDynamicBytesOffset(c.has, *blob)
 }
 
 // DefineDynamicBytesContent defines the next field as dynamic binary blob.
@@ -78,7 +286,50 @@ func DefineDynamicBytesContent(c *Codec, blob *[]byte, maxSize uint32) {
 		EncodeDynamicBytesContent(c.enc, *blob)
 		return
 	}
-	DecodeDynamicBytesContent(c.dec, blob, maxSize)
+	if c.dec != nil {
+		DecodeDynamicBytesContent(c.dec, blob, maxSize)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeDynamicBytes(*blob))
+		return
+	}
+	HashDynamicBytesContent(c.has, *blob, maxSize)
+}
+
+// DefineBitlistOffset defines the next field as a dynamic bitlist.
+func DefineBitlistOffset(c *Codec, bitlist *[]byte) {
+	if c.enc != nil {
+		EncodeBitlistOffset(c.enc, *bitlist)
+		return
+	}
+	if c.dec != nil {
+		DecodeBitlistOffset(c.dec, bitlist)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashBitlistOffset(c.has, *bitlist)
+}
+
+// DefineBitlistContent defines the next field as a dynamic bitlist, capped to a
+// maximum number of bits (excluding the delimiter bit).
+func DefineBitlistContent(c *Codec, bitlist *[]byte, maxBits uint64) {
+	if c.enc != nil {
+		EncodeBitlistContent(c.enc, *bitlist)
+		return
+	}
+	if c.dec != nil {
+		DecodeBitlistContent(c.dec, bitlist, maxBits)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeBitlist(*bitlist))
+		return
+	}
+	HashBitlistContent(c.has, *bitlist, maxBits)
 }
 
 // DefineStaticObject defines the next field as a static ssz object.
@@ -87,7 +338,15 @@ func DefineStaticObject[T newableStaticObject[U], U any](c *Codec, obj *T) {
 		EncodeStaticObject(c.enc, *obj)
 		return
 	}
-	DecodeStaticObject(c.dec, obj)
+	if c.dec != nil {
+		DecodeStaticObject(c.dec, obj)
+		return
+	}
+	if c.siz != nil {
+		sizeStaticObject(c.siz, *obj)
+		return
+	}
+	HashStaticObject(c.has, *obj)
 }
 
 // DefineDynamicObjectOffset defines the next field as a dynamic ssz object.
@@ -96,7 +355,15 @@ func DefineDynamicObjectOffset[T newableDynamicObject[U], U any](c *Codec, obj *
 		EncodeDynamicObjectOffset(c.enc, *obj)
 		return
 	}
-	DecodeDynamicObjectOffset(c.dec, obj)
+	if c.dec != nil {
+		DecodeDynamicObjectOffset(c.dec, obj)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashDynamicObjectOffset(c.has, *obj)
 }
 
 // DefineDynamicObjectContent defines the next field as a dynamic ssz object.
@@ -105,7 +372,32 @@ func DefineDynamicObjectContent[T newableDynamicObject[U], U any](c *Codec, obj
 		EncodeDynamicObjectContent(c.enc, *obj)
 		return
 	}
-	DecodeDynamicObjectContent(c.dec, obj)
+	if c.dec != nil {
+		DecodeDynamicObjectContent(c.dec, obj)
+		return
+	}
+	if c.siz != nil {
+		sizeDynamicObjectContent(c.siz, *obj)
+		return
+	}
+	HashDynamicObjectContent(c.has, *obj)
+}
+
+// DefineArrayOfUint64s defines the next field as a static array of uint64s.
+func DefineArrayOfUint64s[T ~uint64](c *Codec, ns []T) {
+	if c.enc != nil {
+		EncodeArrayOfUint64s(c.enc, ns)
+		return
+	}
+	if c.dec != nil {
+		DecodeArrayOfUint64s(c.dec, ns)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(uint32(8 * len(ns)))
+		return
+	}
+	HashArrayOfUint64s(c.has, ns)
 }
 
 // DefineSliceOfUint64sOffset defines the next field as a dynamic slice of uint64s.
@@ -114,7 +406,15 @@ func DefineSliceOfUint64sOffset[T ~uint64](c *Codec, ns *[]T) {
 		EncodeSliceOfUint64sOffset(c.enc, *ns)
 		return
 	}
-	DecodeSliceOfUint64sOffset(c.dec, ns)
+	if c.dec != nil {
+		DecodeSliceOfUint64sOffset(c.dec, ns)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfUint64sOffset(c.has, *ns)
 }
 
 // DefineSliceOfUint64sContent defines the next field as a dynamic slice of uint64s.
@@ -123,37 +423,176 @@ func DefineSliceOfUint64sContent[T ~uint64](c *Codec, ns *[]T, maxItems uint32)
 		EncodeSliceOfUint64sContent(c.enc, *ns)
 		return
 	}
-	DecodeSliceOfUint64sContent(c.dec, ns, maxItems)
+	if c.dec != nil {
+		DecodeSliceOfUint64sContent(c.dec, ns, maxItems)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfUint64s(*ns))
+		return
+	}
+	HashSliceOfUint64sContent(c.has, *ns, maxItems)
+}
+
+// DefineArrayOfBools defines the next field as a static array of booleans.
+func DefineArrayOfBools[T ~bool](c *Codec, vs []T) {
+	if c.enc != nil {
+		EncodeArrayOfBools(c.enc, vs)
+		return
+	}
+	if c.dec != nil {
+		DecodeArrayOfBools(c.dec, vs)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(uint32(len(vs)))
+		return
+	}
+	HashArrayOfBools(c.has, vs)
+}
+
+// DefineSliceOfBoolsOffset defines the next field as a dynamic slice of booleans.
+func DefineSliceOfBoolsOffset[T ~bool](c *Codec, vs *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfBoolsOffset(c.enc, *vs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfBoolsOffset(c.dec, vs)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfBoolsOffset(c.has, *vs)
+}
+
+// DefineSliceOfBoolsContent defines the next field as a dynamic slice of booleans.
+func DefineSliceOfBoolsContent[T ~bool](c *Codec, vs *[]T, maxItems uint32) {
+	if c.enc != nil {
+		EncodeSliceOfBoolsContent(c.enc, *vs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfBoolsContent(c.dec, vs, maxItems)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfBools(*vs))
+		return
+	}
+	HashSliceOfBoolsContent(c.has, *vs, maxItems)
 }
 
 // DefineArrayOfStaticBytes defines the next field as a static array of static
 // binary blobs.
-func DefineArrayOfStaticBytes[T commonBinaryLengths](c *Codec, bytes []T) {
+func DefineArrayOfStaticBytes[T staticBinary](c *Codec, bytes []T) {
 	if c.enc != nil {
 		EncodeArrayOfStaticBytes(c.enc, bytes)
 		return
 	}
-	DecodeArrayOfStaticBytes(c.dec, bytes)
+	if c.dec != nil {
+		DecodeArrayOfStaticBytes(c.dec, bytes)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(SizeSliceOfStaticBytes(bytes))
+		return
+	}
+	HashArrayOfStaticBytes(c.has, bytes)
 }
 
 // DefineSliceOfStaticBytesOffset defines the next field as a dynamic slice of static
 // binary blobs.
-func DefineSliceOfStaticBytesOffset[T commonBinaryLengths](c *Codec, bytes *[]T) {
+func DefineSliceOfStaticBytesOffset[T staticBinary](c *Codec, bytes *[]T) {
 	if c.enc != nil {
 		EncodeSliceOfStaticBytesOffset(c.enc, *bytes)
 		return
 	}
-	DecodeSliceOfStaticBytesOffset(c.dec, bytes)
+	if c.dec != nil {
+		DecodeSliceOfStaticBytesOffset(c.dec, bytes)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfStaticBytesOffset(c.has, *bytes)
 }
 
 // DefineSliceOfStaticBytesContent defines the next field as a dynamic slice of static
 // binary blobs.
-func DefineSliceOfStaticBytesContent[T commonBinaryLengths](c *Codec, bytes *[]T, maxItems uint32) {
+func DefineSliceOfStaticBytesContent[T staticBinary](c *Codec, bytes *[]T, maxItems uint32) {
 	if c.enc != nil {
 		EncodeSliceOfStaticBytesContent(c.enc, *bytes)
 		return
 	}
-	DecodeSliceOfStaticBytesContent(c.dec, bytes, maxItems)
+	if c.dec != nil {
+		DecodeSliceOfStaticBytesContent(c.dec, bytes, maxItems)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfStaticBytes(*bytes))
+		return
+	}
+	HashSliceOfStaticBytesContent(c.has, *bytes, maxItems)
+}
+
+// DefineArrayOfStridedBytes defines the next field as a static array of static
+// binary blobs of stride bytes each, flattened into a single byte slice. It is
+// meant for blob sizes DefineArrayOfStaticBytes does not accept.
+func DefineArrayOfStridedBytes(c *Codec, blobs []byte, stride uint32) {
+	if c.enc != nil {
+		EncodeArrayOfStridedBytes(c.enc, blobs, stride)
+		return
+	}
+	if c.dec != nil {
+		DecodeArrayOfStridedBytes(c.dec, blobs, stride)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addStatic(uint32(len(blobs)))
+		return
+	}
+	HashArrayOfStridedBytes(c.has, blobs, stride)
+}
+
+// DefineSliceOfStridedBytesOffset defines the next field as a dynamic slice of
+// static binary blobs of stride bytes each, flattened into a single byte slice.
+func DefineSliceOfStridedBytesOffset(c *Codec, blobs *[]byte, stride uint32) {
+	if c.enc != nil {
+		EncodeSliceOfStridedBytesOffset(c.enc, *blobs, stride)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfStridedBytesOffset(c.dec, blobs, stride)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfStridedBytesOffset(c.has, *blobs, stride)
+}
+
+// DefineSliceOfStridedBytesContent defines the next field as a dynamic slice of
+// static binary blobs of stride bytes each, flattened into a single byte slice.
+// It is meant for blob sizes DefineSliceOfStaticBytesContent does not accept.
+func DefineSliceOfStridedBytesContent(c *Codec, blobs *[]byte, stride uint32, maxItems uint32) {
+	if c.enc != nil {
+		EncodeSliceOfStridedBytesContent(c.enc, *blobs, stride)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfStridedBytesContent(c.dec, blobs, stride, maxItems)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfStridedBytes(*blobs))
+		return
+	}
+	HashSliceOfStridedBytesContent(c.has, *blobs, stride, maxItems)
 }
 
 // DefineSliceOfDynamicBytesOffset defines the next field as a dynamic slice of dynamic
@@ -163,7 +602,15 @@ func DefineSliceOfDynamicBytesOffset(c *Codec, blobs *[][]byte) {
 		EncodeSliceOfDynamicBytesOffset(c.enc, *blobs)
 		return
 	}
-	DecodeSliceOfDynamicBytesOffset(c.dec, blobs)
+	if c.dec != nil {
+		DecodeSliceOfDynamicBytesOffset(c.dec, blobs)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfDynamicBytesOffset(c.has, *blobs)
 }
 
 // DefineSliceOfDynamicBytesContent defines the next field as a dynamic slice of dynamic
@@ -173,7 +620,33 @@ func DefineSliceOfDynamicBytesContent(c *Codec, blobs *[][]byte, maxItems uint32
 		EncodeSliceOfDynamicBytesContent(c.enc, *blobs)
 		return
 	}
-	DecodeSliceOfDynamicBytesContent(c.dec, blobs, maxItems, maxSize)
+	if c.dec != nil {
+		DecodeSliceOfDynamicBytesContent(c.dec, blobs, maxItems, maxSize)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfDynamicBytes(*blobs))
+		return
+	}
+	HashSliceOfDynamicBytesContent(c.has, *blobs, maxItems, maxSize)
+}
+
+// DefineArrayOfStaticObjects defines the next field as a static array of static
+// objects.
+func DefineArrayOfStaticObjects[T newableStaticObject[U], U any](c *Codec, objects []T) {
+	if c.enc != nil {
+		EncodeArrayOfStaticObjects(c.enc, objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeArrayOfStaticObjects(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		sizeArrayOfStaticObjects(c.siz, objects)
+		return
+	}
+	HashArrayOfStaticObjects(c.has, objects)
 }
 
 // DefineSliceOfStaticObjectsOffset defines the next field as a dynamic slice of static
@@ -183,7 +656,15 @@ func DefineSliceOfStaticObjectsOffset[T newableStaticObject[U], U any](c *Codec,
 		EncodeSliceOfStaticObjectsOffset(c.enc, *objects)
 		return
 	}
-	DecodeSliceOfStaticObjectsOffset(c.dec, objects)
+	if c.dec != nil {
+		DecodeSliceOfStaticObjectsOffset(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfStaticObjectsOffset(c.has, *objects)
 }
 
 // DefineSliceOfStaticObjectsContent defines the next field as a dynamic slice of static
@@ -193,7 +674,15 @@ func DefineSliceOfStaticObjectsContent[T newableStaticObject[U], U any](c *Codec
 		EncodeSliceOfStaticObjectsContent(c.enc, *objects)
 		return
 	}
-	DecodeSliceOfStaticObjectsContent(c.dec, objects, maxItems)
+	if c.dec != nil {
+		DecodeSliceOfStaticObjectsContent(c.dec, objects, maxItems)
+		return
+	}
+	if c.siz != nil {
+		sizeSliceOfStaticObjectsContent(c.siz, *objects)
+		return
+	}
+	HashSliceOfStaticObjectsContent(c.has, *objects, maxItems)
 }
 
 // DefineSliceOfDynamicObjectsOffset defines the next field as a dynamic slice of dynamic
@@ -203,7 +692,15 @@ func DefineSliceOfDynamicObjectsOffset[T newableDynamicObject[U], U any](c *Code
 		EncodeSliceOfDynamicObjectsOffset(c.enc, *objects)
 		return
 	}
-	DecodeSliceOfDynamicObjectsOffset(c.dec, objects)
+	if c.dec != nil {
+		DecodeSliceOfDynamicObjectsOffset(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfDynamicObjectsOffset(c.has, *objects)
 }
 
 // DefineSliceOfDynamicObjectsContent defines the next field as a dynamic slice of dynamic
@@ -213,5 +710,381 @@ func DefineSliceOfDynamicObjectsContent[T newableDynamicObject[U], U any](c *Cod
 		EncodeSliceOfDynamicObjectsContent(c.enc, *objects)
 		return
 	}
-	DecodeSliceOfDynamicObjectsContent(c.dec, objects, maxItems)
+	if c.dec != nil {
+		DecodeSliceOfDynamicObjectsContent(c.dec, objects, maxItems)
+		return
+	}
+	if c.siz != nil {
+		sizeSliceOfDynamicObjectsContent(c.siz, *objects)
+		return
+	}
+	HashSliceOfDynamicObjectsContent(c.has, *objects, maxItems)
+}
+
+// DefineArrayOfDynamicObjectsOffset defines the next field as a static array of dynamic
+// objects.
+func DefineArrayOfDynamicObjectsOffset[T newableDynamicObject[U], U any](c *Codec, objects []T) {
+	if c.enc != nil {
+		EncodeArrayOfDynamicObjectsOffset(c.enc, objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeArrayOfDynamicObjectsOffset(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashArrayOfDynamicObjectsOffset(c.has, objects)
+}
+
+// DefineArrayOfDynamicObjectsContent defines the next field as a static array of dynamic
+// objects.
+func DefineArrayOfDynamicObjectsContent[T newableDynamicObject[U], U any](c *Codec, objects []T) {
+	if c.enc != nil {
+		EncodeArrayOfDynamicObjectsContent(c.enc, objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeArrayOfDynamicObjectsContent(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		sizeSliceOfDynamicObjectsContent(c.siz, objects)
+		return
+	}
+	HashArrayOfDynamicObjectsContent(c.has, objects)
+}
+
+// DefineUnionOffset defines the next field as a dynamic union.
+func DefineUnionOffset[S ~uint8](c *Codec, selector *S, value *Object) {
+	if c.enc != nil {
+		EncodeUnionOffset(c.enc, *selector, *value)
+		return
+	}
+	if c.dec != nil {
+		DecodeUnionOffset(c.dec, selector, value)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashUnionOffset(c.has, *selector, *value)
+}
+
+// DefineUnionContent defines the next field as a dynamic union. The variants are
+// constructors for the union's possible values, indexed by the selector, with a
+// nil constructor denoting the None variant (only permitted as the first one).
+func DefineUnionContent[S ~uint8](c *Codec, selector *S, value *Object, variants ...func() Object) {
+	if c.enc != nil {
+		EncodeUnionContent(c.enc, *selector, *value, variants)
+		return
+	}
+	if c.dec != nil {
+		DecodeUnionContent(c.dec, selector, value, variants)
+		return
+	}
+	if c.siz != nil {
+		sizeUnionContent(c.siz, *value)
+		return
+	}
+	HashUnionContent(c.has, *selector, *value)
+}
+
+// DefineProgressiveDynamicBytesOffset defines the next field as a progressive binary
+// blob (EIP-7916).
+func DefineProgressiveDynamicBytesOffset(c *Codec, blob *[]byte) {
+	if c.enc != nil {
+		EncodeDynamicBytesOffset(c.enc, *blob)
+		return
+	}
+	if c.dec != nil {
+		DecodeDynamicBytesOffset(c.dec, blob)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashDynamicBytesOffset(c.has, *blob)
+}
+
+// DefineProgressiveDynamicBytesContent defines the next field as a progressive
+// binary blob (EIP-7916). The number of bytes accepted when decoding is capped by
+// the decoder's safety limit.
+func DefineProgressiveDynamicBytesContent(c *Codec, blob *[]byte) {
+	if c.enc != nil {
+		EncodeDynamicBytesContent(c.enc, *blob)
+		return
+	}
+	if c.dec != nil {
+		DecodeDynamicBytesContent(c.dec, blob, c.dec.limit)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeDynamicBytes(*blob))
+		return
+	}
+	HashProgressiveDynamicBytesContent(c.has, *blob)
+}
+
+// DefineProgressiveBitlistOffset defines the next field as a progressive bitlist
+// (EIP-7916).
+func DefineProgressiveBitlistOffset(c *Codec, bitlist *[]byte) {
+	if c.enc != nil {
+		EncodeBitlistOffset(c.enc, *bitlist)
+		return
+	}
+	if c.dec != nil {
+		DecodeBitlistOffset(c.dec, bitlist)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashBitlistOffset(c.has, *bitlist)
+}
+
+// DefineProgressiveBitlistContent defines the next field as a progressive bitlist
+// (EIP-7916). The number of bits accepted when decoding is capped by the decoder's
+// safety limit.
+func DefineProgressiveBitlistContent(c *Codec, bitlist *[]byte) {
+	if c.enc != nil {
+		EncodeBitlistContent(c.enc, *bitlist)
+		return
+	}
+	if c.dec != nil {
+		DecodeBitlistContent(c.dec, bitlist, uint64(c.dec.limit))
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeBitlist(*bitlist))
+		return
+	}
+	HashProgressiveBitlistContent(c.has, *bitlist)
+}
+
+// DefineProgressiveListOfUint64sOffset defines the next field as a progressive list
+// of uint64s (EIP-7916).
+func DefineProgressiveListOfUint64sOffset[T ~uint64](c *Codec, ns *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfUint64sOffset(c.enc, *ns)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfUint64sOffset(c.dec, ns)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfUint64sOffset(c.has, *ns)
+}
+
+// DefineProgressiveListOfUint64sContent defines the next field as a progressive list
+// of uint64s (EIP-7916). The number of items accepted when decoding is capped by the
+// decoder's safety limit.
+func DefineProgressiveListOfUint64sContent[T ~uint64](c *Codec, ns *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfUint64sContent(c.enc, *ns)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfUint64sContent(c.dec, ns, c.dec.limit)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfUint64s(*ns))
+		return
+	}
+	HashProgressiveListOfUint64sContent(c.has, *ns)
+}
+
+// DefineProgressiveListOfBoolsOffset defines the next field as a progressive list of
+// booleans (EIP-7916).
+func DefineProgressiveListOfBoolsOffset[T ~bool](c *Codec, vs *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfBoolsOffset(c.enc, *vs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfBoolsOffset(c.dec, vs)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfBoolsOffset(c.has, *vs)
+}
+
+// DefineProgressiveListOfBoolsContent defines the next field as a progressive list
+// of booleans (EIP-7916). The number of items accepted when decoding is capped by
+// the decoder's safety limit.
+func DefineProgressiveListOfBoolsContent[T ~bool](c *Codec, vs *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfBoolsContent(c.enc, *vs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfBoolsContent(c.dec, vs, c.dec.limit)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfBools(*vs))
+		return
+	}
+	HashProgressiveListOfBoolsContent(c.has, *vs)
+}
+
+// DefineProgressiveListOfStaticBytesOffset defines the next field as a progressive
+// list of static binary blobs (EIP-7916).
+func DefineProgressiveListOfStaticBytesOffset[T staticBinary](c *Codec, blobs *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfStaticBytesOffset(c.enc, *blobs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfStaticBytesOffset(c.dec, blobs)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfStaticBytesOffset(c.has, *blobs)
+}
+
+// DefineProgressiveListOfStaticBytesContent defines the next field as a progressive
+// list of static binary blobs (EIP-7916). The number of items accepted when decoding
+// is capped by the decoder's safety limit.
+func DefineProgressiveListOfStaticBytesContent[T staticBinary](c *Codec, blobs *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfStaticBytesContent(c.enc, *blobs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfStaticBytesContent(c.dec, blobs, c.dec.limit)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfStaticBytes(*blobs))
+		return
+	}
+	HashProgressiveListOfStaticBytesContent(c.has, *blobs)
+}
+
+// DefineProgressiveListOfDynamicBytesOffset defines the next field as a progressive
+// list of dynamic binary blobs (EIP-7916).
+func DefineProgressiveListOfDynamicBytesOffset(c *Codec, blobs *[][]byte) {
+	if c.enc != nil {
+		EncodeSliceOfDynamicBytesOffset(c.enc, *blobs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfDynamicBytesOffset(c.dec, blobs)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfDynamicBytesOffset(c.has, *blobs)
+}
+
+// DefineProgressiveListOfDynamicBytesContent defines the next field as a progressive
+// list of dynamic binary blobs (EIP-7916). The number of items accepted when
+// decoding is capped by the decoder's safety limit, the items themselves to maxSize.
+func DefineProgressiveListOfDynamicBytesContent(c *Codec, blobs *[][]byte, maxSize uint32) {
+	if c.enc != nil {
+		EncodeSliceOfDynamicBytesContent(c.enc, *blobs)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfDynamicBytesContent(c.dec, blobs, c.dec.limit, maxSize)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addDynamic(SizeSliceOfDynamicBytes(*blobs))
+		return
+	}
+	HashProgressiveListOfDynamicBytesContent(c.has, *blobs, maxSize)
+}
+
+// DefineProgressiveListOfStaticObjectsOffset defines the next field as a progressive
+// list of static objects (EIP-7916).
+func DefineProgressiveListOfStaticObjectsOffset[T newableStaticObject[U], U any](c *Codec, objects *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfStaticObjectsOffset(c.enc, *objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfStaticObjectsOffset(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfStaticObjectsOffset(c.has, *objects)
+}
+
+// DefineProgressiveListOfStaticObjectsContent defines the next field as a
+// progressive list of static objects (EIP-7916). The number of items accepted when
+// decoding is capped by the decoder's safety limit.
+func DefineProgressiveListOfStaticObjectsContent[T newableStaticObject[U], U any](c *Codec, objects *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfStaticObjectsContent(c.enc, *objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfStaticObjectsContent(c.dec, objects, c.dec.limit)
+		return
+	}
+	if c.siz != nil {
+		sizeSliceOfStaticObjectsContent(c.siz, *objects)
+		return
+	}
+	HashProgressiveListOfStaticObjectsContent(c.has, *objects)
+}
+
+// DefineProgressiveListOfDynamicObjectsOffset defines the next field as a
+// progressive list of dynamic objects (EIP-7916).
+func DefineProgressiveListOfDynamicObjectsOffset[T newableDynamicObject[U], U any](c *Codec, objects *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfDynamicObjectsOffset(c.enc, *objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfDynamicObjectsOffset(c.dec, objects)
+		return
+	}
+	if c.siz != nil {
+		c.siz.addOffset()
+		return
+	}
+	HashSliceOfDynamicObjectsOffset(c.has, *objects)
+}
+
+// DefineProgressiveListOfDynamicObjectsContent defines the next field as a
+// progressive list of dynamic objects (EIP-7916). The number of items accepted when
+// decoding is capped by the decoder's safety limit.
+func DefineProgressiveListOfDynamicObjectsContent[T newableDynamicObject[U], U any](c *Codec, objects *[]T) {
+	if c.enc != nil {
+		EncodeSliceOfDynamicObjectsContent(c.enc, *objects)
+		return
+	}
+	if c.dec != nil {
+		DecodeSliceOfDynamicObjectsContent(c.dec, objects, c.dec.limit)
+		return
+	}
+	if c.siz != nil {
+		sizeSliceOfDynamicObjectsContent(c.siz, *objects)
+		return
+	}
+	HashProgressiveListOfDynamicObjectsContent(c.has, *objects)
 }
@@ -8,7 +8,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
-	"unsafe"
+	"math/bits"
 
 	"github.com/holiman/uint256"
 )
@@ -25,9 +25,10 @@ import (
 //     error checking is done at the end. Internally, of course, an error will
 //     halt all future input operations.
 type Decoder struct {
-	inReader io.Reader // Underlying input stream to read from (streaming mode)
-	inBuffer []byte    // Underlying input stream to read from (buffered mode)
-	err      error     // Any write error to halt future encoding calls
+	inReader io.Reader        // Underlying input stream to read from (streaming mode)
+	inLimit  io.LimitedReader // Size cap on the input stream to read from (streaming mode)
+	inBuffer []byte           // Underlying input stream to read from (buffered mode)
+	err      error            // Any write error to halt future encoding calls
 
 	codec *Codec   // Self-referencing to pass DefineSSZ calls through (API trick)
 	buf   [32]byte // Integer conversion buffer
@@ -40,6 +41,98 @@ type Decoder struct {
 
 	sizes  []uint32   // Computed sizes for the dynamic objects
 	sizess [][]uint32 // Stack of computed sizes from outer calls
+
+	field  string     // Name of the field being decoded
+	names  []string   // Names of the dynamic fields, matching the computed sizes
+	namess [][]string // Stack of dynamic field names from outer calls
+
+	next string      // Name attached to the next field via Codec.Named
+	mark stableState // StableContainer layout at the time the name was attached
+
+	limit uint32 // Safety cap on the number of items in progressive lists
+}
+
+// DecodeBool parses a boolean.
+func DecodeBool[T ~bool](dec *Decoder, v *T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	var b byte
+	if dec.inReader != nil {
+		if _, dec.err = io.ReadFull(dec.inReader, dec.buf[:1]); dec.err != nil {
+			return
+		}
+		b = dec.buf[0]
+	} else {
+		if dec.short(1) {
+			return
+		}
+		b = dec.inBuffer[0]
+		dec.inBuffer = dec.inBuffer[1:]
+	}
+	switch b {
+	case 0:
+		*v = false
+	case 1:
+		*v = true
+	default:
+		dec.err = fmt.Errorf("%w: found %#x", ErrInvalidBoolean, b)
+	}
+}
+
+// DecodeUint8 parses a uint8.
+func DecodeUint8[T ~uint8](dec *Decoder, n *T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	if dec.inReader != nil {
+		_, dec.err = io.ReadFull(dec.inReader, dec.buf[:1])
+		*n = T(dec.buf[0])
+	} else {
+		if dec.short(1) {
+			return
+		}
+		*n = T(dec.inBuffer[0])
+		dec.inBuffer = dec.inBuffer[1:]
+	}
+}
+
+// DecodeUint16 parses a uint16.
+func DecodeUint16[T ~uint16](dec *Decoder, n *T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	if dec.inReader != nil {
+		_, dec.err = io.ReadFull(dec.inReader, dec.buf[:2])
+		*n = T(binary.LittleEndian.Uint16(dec.buf[:2]))
+	} else {
+		if dec.short(2) {
+			return
+		}
+		*n = T(binary.LittleEndian.Uint16(dec.inBuffer))
+		dec.inBuffer = dec.inBuffer[2:]
+	}
+}
+
+// DecodeUint32 parses a uint32.
+func DecodeUint32[T ~uint32](dec *Decoder, n *T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	if dec.inReader != nil {
+		_, dec.err = io.ReadFull(dec.inReader, dec.buf[:4])
+		*n = T(binary.LittleEndian.Uint32(dec.buf[:4]))
+	} else {
+		if dec.short(4) {
+			return
+		}
+		*n = T(binary.LittleEndian.Uint32(dec.inBuffer))
+		dec.inBuffer = dec.inBuffer[4:]
+	}
 }
 
 // DecodeUint64 parses a uint64.
@@ -47,20 +140,46 @@ func DecodeUint64[T ~uint64](dec *Decoder, n *T) {
 	if dec.err != nil {
 		return
 	}
+	dec.begin()
 	if dec.inReader != nil {
 		_, dec.err = io.ReadFull(dec.inReader, dec.buf[:8])
 		*n = T(binary.LittleEndian.Uint64(dec.buf[:8]))
 	} else {
+		if dec.short(8) {
+			return
+		}
 		*n = T(binary.LittleEndian.Uint64(dec.inBuffer))
 		dec.inBuffer = dec.inBuffer[8:]
 	}
 }
 
+// DecodeUint128 parses a uint128 into two uint64 limbs in little endian order
+// (least significant first).
+func DecodeUint128[T ~[2]uint64](dec *Decoder, n *T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	if dec.inReader != nil {
+		_, dec.err = io.ReadFull(dec.inReader, dec.buf[:16])
+		(*n)[0] = binary.LittleEndian.Uint64(dec.buf[:8])
+		(*n)[1] = binary.LittleEndian.Uint64(dec.buf[8:16])
+	} else {
+		if dec.short(16) {
+			return
+		}
+		(*n)[0] = binary.LittleEndian.Uint64(dec.inBuffer)
+		(*n)[1] = binary.LittleEndian.Uint64(dec.inBuffer[8:])
+		dec.inBuffer = dec.inBuffer[16:]
+	}
+}
+
 // DecodeUint256 parses a uint256.
 func DecodeUint256(dec *Decoder, n **uint256.Int) {
 	if dec.err != nil {
 		return
 	}
+	dec.begin()
 	if dec.inReader != nil {
 		_, dec.err = io.ReadFull(dec.inReader, dec.buf[:32])
 		if dec.err != nil {
@@ -71,6 +190,9 @@ func DecodeUint256(dec *Decoder, n **uint256.Int) {
 		}
 		(*n).UnmarshalSSZ(dec.buf[:32])
 	} else {
+		if dec.short(32) {
+			return
+		}
 		if *n == nil {
 			*n = new(uint256.Int)
 		}
@@ -84,11 +206,34 @@ func DecodeStaticBytes(dec *Decoder, blob []byte) {
 	if dec.err != nil {
 		return
 	}
+	dec.begin()
+	decodeBytes(dec, blob)
+}
+
+// DecodeBitvector parses a static bitvector of the given size (in bits).
+//
+// Note, the input slice is assumed to be pre-allocated to (size+7)/8 bytes.
+func DecodeBitvector(dec *Decoder, bits []byte, size uint64) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
 	if dec.inReader != nil {
-		_, dec.err = io.ReadFull(dec.inReader, blob)
+		if _, dec.err = io.ReadFull(dec.inReader, bits); dec.err != nil {
+			return
+		}
 	} else {
-		copy(blob, dec.inBuffer)
-		dec.inBuffer = dec.inBuffer[len(blob):]
+		if dec.short(len(bits)) {
+			return
+		}
+		copy(bits, dec.inBuffer)
+		dec.inBuffer = dec.inBuffer[len(bits):]
+	}
+	// Ensure the unused bits in the last byte are all zero
+	if rem := size & 7; rem != 0 {
+		if junk := bits[len(bits)-1] >> rem; junk != 0 {
+			dec.err = fmt.Errorf("%w: last byte %#x, size %d bits", ErrJunkInBitvector, bits[len(bits)-1], size)
+		}
 	}
 }
 
@@ -120,11 +265,66 @@ func DecodeDynamicBytesContent(dec *Decoder, blob *[]byte, maxSize uint32) {
 	if dec.inReader != nil {
 		_, dec.err = io.ReadFull(dec.inReader, *blob)
 	} else {
+		if dec.short(int(size)) {
+			return
+		}
 		copy(*blob, dec.inBuffer)
 		dec.inBuffer = dec.inBuffer[size:]
 	}
 }
 
+// DecodeBitlistOffset parses a dynamic bitlist.
+func DecodeBitlistOffset(dec *Decoder, bitlist *[]byte) {
+	dec.decodeOffset(false)
+}
+
+// DecodeBitlistContent is the lazy data reader of DecodeBitlistOffset.
+func DecodeBitlistContent(dec *Decoder, bitlist *[]byte, maxBits uint64) {
+	if dec.err != nil {
+		return
+	}
+	// Compute the length of the bitlist based on the seen offsets. Even an empty
+	// bitlist contains the delimiter bit, so it needs at least one byte.
+	size := dec.retrieveSize()
+	if size == 0 {
+		dec.err = fmt.Errorf("%w: empty bitlist", ErrJunkInBitlist)
+		return
+	}
+	if uint64(size) > maxBits>>3+1 {
+		dec.err = fmt.Errorf("%w: decoded %d bytes, max %d bits", ErrMaxItemsExceeded, size, maxBits)
+		return
+	}
+	// Expand the byte slice if needed and fill it with the data
+	if uint32(cap(*bitlist)) < size {
+		*bitlist = make([]byte, size)
+	} else {
+		*bitlist = (*bitlist)[:size]
+	}
+	// Inline:
+	//
+	// DecodeStaticBytes(dec, *(bitlist))
+	if dec.inReader != nil {
+		if _, dec.err = io.ReadFull(dec.inReader, *bitlist); dec.err != nil {
+			return
+		}
+	} else {
+		if dec.short(int(size)) {
+			return
+		}
+		copy(*bitlist, dec.inBuffer)
+		dec.inBuffer = dec.inBuffer[size:]
+	}
+	// Ensure the delimiter bit is present and the bit count is within limits
+	last := (*bitlist)[size-1]
+	if last == 0 {
+		dec.err = fmt.Errorf("%w: missing delimiter bit", ErrJunkInBitlist)
+		return
+	}
+	if count := uint64(size-1)<<3 + uint64(bits.Len8(last)-1); count > maxBits {
+		dec.err = fmt.Errorf("%w: decoded %d bits, max %d", ErrMaxItemsExceeded, count, maxBits)
+	}
+}
+
 // DecodeStaticObject parses a static ssz object.
 func DecodeStaticObject[T newableStaticObject[U], U any](dec *Decoder, obj *T) {
 	if dec.err != nil {
@@ -133,7 +333,12 @@ func DecodeStaticObject[T newableStaticObject[U], U any](dec *Decoder, obj *T) {
 	if *obj == nil {
 		*obj = T(new(U))
 	}
-	(*obj).DefineSSZ(dec.codec)
+	dec.begin()
+	name := dec.field
+	dec.field = ""
+	if (*obj).DefineSSZ(dec.codec); dec.err != nil {
+		dec.trace(name, -1)
+	}
 }
 
 // DecodeDynamicObjectOffset parses a dynamic ssz object.
@@ -157,11 +362,42 @@ func DecodeDynamicObjectContent[T newableDynamicObject[U], U any](dec *Decoder,
 	if *obj == nil {
 		*obj = T(new(U))
 	}
+	name := dec.field
+	dec.field = ""
+
 	dec.startDynamics((*obj).SizeSSZ(true))
-	(*obj).DefineSSZ(dec.codec)
+	if (*obj).DefineSSZ(dec.codec); dec.err != nil {
+		dec.trace(name, -1)
+	}
 	dec.flushDynamics()
 }
 
+// DecodeArrayOfUint64s parses a static array of uint64s.
+//
+// Note, the input slice is assumed to be pre-allocated.
+func DecodeArrayOfUint64s[T ~uint64](dec *Decoder, ns []T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	if dec.inReader != nil {
+		for i := range ns {
+			if _, dec.err = io.ReadFull(dec.inReader, dec.buf[:8]); dec.err != nil {
+				return
+			}
+			ns[i] = T(binary.LittleEndian.Uint64(dec.buf[:8]))
+		}
+	} else {
+		if dec.short(8 * len(ns)) {
+			return
+		}
+		for i := range ns {
+			ns[i] = T(binary.LittleEndian.Uint64(dec.inBuffer))
+			dec.inBuffer = dec.inBuffer[8:]
+		}
+	}
+}
+
 // DecodeSliceOfUint64sOffset parses a dynamic slice of uint64s.
 func DecodeSliceOfUint64sOffset[T ~uint64](dec *Decoder, ns *[]T) {
 	dec.decodeOffset(false)
@@ -193,6 +429,9 @@ func DecodeSliceOfUint64sContent[T ~uint64](dec *Decoder, ns *[]T, maxItems uint
 	} else {
 		*ns = (*ns)[:itemCount]
 	}
+	if dec.inReader == nil && dec.short(int(size)) {
+		return
+	}
 	for i := uint32(0); i < itemCount; i++ {
 		if dec.inReader != nil {
 			_, dec.err = io.ReadFull(dec.inReader, dec.buf[:8])
@@ -207,39 +446,76 @@ func DecodeSliceOfUint64sContent[T ~uint64](dec *Decoder, ns *[]T, maxItems uint
 	}
 }
 
+// DecodeArrayOfBools parses a static array of booleans.
+//
+// Note, the input slice is assumed to be pre-allocated.
+func DecodeArrayOfBools[T ~bool](dec *Decoder, vs []T) {
+	if dec.err != nil {
+		return
+	}
+	dec.begin()
+	decodeBools(dec, vs)
+}
+
+// DecodeSliceOfBoolsOffset parses a dynamic slice of booleans.
+func DecodeSliceOfBoolsOffset[T ~bool](dec *Decoder, vs *[]T) {
+	dec.decodeOffset(false)
+}
+
+// DecodeSliceOfBoolsContent is the lazy data reader of DecodeSliceOfBoolsOffset.
+func DecodeSliceOfBoolsContent[T ~bool](dec *Decoder, vs *[]T, maxItems uint32) {
+	if dec.err != nil {
+		return
+	}
+	// Compute the number of items based on the seen offsets
+	size := dec.retrieveSize()
+	if size > maxItems {
+		dec.err = fmt.Errorf("%w: decoded %d, max %d", ErrMaxItemsExceeded, size, maxItems)
+		return
+	}
+	// Expand the slice if needed and decode the booleans
+	if uint32(cap(*vs)) < size {
+		*vs = make([]T, size)
+	} else {
+		*vs = (*vs)[:size]
+	}
+	decodeBools(dec, *vs)
+}
+
 // DecodeArrayOfStaticBytes parses a static array of static binary blobs.
 //
 // Note, the input slice is assumed to be pre-allocated.
-func DecodeArrayOfStaticBytes[T commonBinaryLengths](dec *Decoder, blobs []T) {
+func DecodeArrayOfStaticBytes[T staticBinary](dec *Decoder, blobs []T) {
 	if dec.err != nil {
 		return
 	}
+	dec.begin()
 	if dec.inReader != nil {
 		for i := 0; i < len(blobs); i++ {
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			_, dec.err = io.ReadFull(dec.inReader, unsafe.Slice(&blobs[i][0], len(blobs[i])))
+			_, dec.err = io.ReadFull(dec.inReader, binaryBytes(&blobs[i]))
 			if dec.err != nil {
 				return
 			}
 		}
 	} else {
 		for i := 0; i < len(blobs); i++ {
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			copy(unsafe.Slice(&blobs[i][0], len(blobs[i])), dec.inBuffer)
-			dec.inBuffer = dec.inBuffer[len(blobs[i]):]
+			blob := binaryBytes(&blobs[i])
+			if dec.short(len(blob)) {
+				return
+			}
+			copy(blob, dec.inBuffer)
+			dec.inBuffer = dec.inBuffer[len(blob):]
 		}
 	}
 }
 
 // DecodeSliceOfStaticBytesOffset parses a dynamic slice of static binary blobs.
-func DecodeSliceOfStaticBytesOffset[T commonBinaryLengths](dec *Decoder, blobs *[]T) {
+func DecodeSliceOfStaticBytesOffset[T staticBinary](dec *Decoder, blobs *[]T) {
 	dec.decodeOffset(false)
 }
 
 // DecodeSliceOfStaticBytesContent is the lazy data reader of DecodeSliceOfStaticBytesOffset.
-func DecodeSliceOfStaticBytesContent[T commonBinaryLengths](dec *Decoder, blobs *[]T, maxItems uint32) {
+func DecodeSliceOfStaticBytesContent[T staticBinary](dec *Decoder, blobs *[]T, maxItems uint32) {
 	if dec.err != nil {
 		return
 	}
@@ -248,10 +524,14 @@ func DecodeSliceOfStaticBytesContent[T commonBinaryLengths](dec *Decoder, blobs
 	if size == 0 {
 		return // empty slice of objects
 	}
-	// Compute the number of items based on the item size of the type
-	var sizer T // SizeSSZ is on *U, objects is static, so nil T is fine
+	// Compute the number of items based on the item size of the type. Byte slices
+	// have no inherent size, so they cannot be decoded from a dynamic list.
+	var sizer T
 
 	itemSize := uint32(len(sizer))
+	if itemSize == 0 {
+		panic(fmt.Sprintf("unsupported dynamic list item type: %T", sizer))
+	}
 	if size%itemSize != 0 {
 		dec.err = fmt.Errorf("%w: length %d, item size %d", ErrDynamicStaticsIndivisible, size, itemSize)
 		return
@@ -269,23 +549,63 @@ func DecodeSliceOfStaticBytesContent[T commonBinaryLengths](dec *Decoder, blobs
 	}
 	if dec.inReader != nil {
 		for i := uint32(0); i < itemCount; i++ {
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			_, dec.err = io.ReadFull(dec.inReader, unsafe.Slice(&(*blobs)[i][0], len((*blobs)[i])))
+			_, dec.err = io.ReadFull(dec.inReader, binaryBytes(&(*blobs)[i]))
 			if dec.err != nil {
 				return
 			}
 		}
 	} else {
+		if dec.short(int(size)) {
+			return
+		}
 		for i := uint32(0); i < itemCount; i++ {
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			copy(unsafe.Slice(&(*blobs)[i][0], len((*blobs)[i])), dec.inBuffer)
-			dec.inBuffer = dec.inBuffer[len((*blobs)[i]):]
+			copy(binaryBytes(&(*blobs)[i]), dec.inBuffer)
+			dec.inBuffer = dec.inBuffer[itemSize:]
 		}
 	}
 }
 
+// DecodeArrayOfStridedBytes parses a static array of static binary blobs,
+// flattened into a single byte slice.
+//
+// Note, the input slice is assumed to be pre-allocated.
+func DecodeArrayOfStridedBytes(dec *Decoder, blobs []byte, stride uint32) {
+	checkStride(blobs, stride)
+	DecodeStaticBytes(dec, blobs)
+}
+
+// DecodeSliceOfStridedBytesOffset parses a dynamic slice of static binary blobs,
+// flattened into a single byte slice.
+func DecodeSliceOfStridedBytesOffset(dec *Decoder, blobs *[]byte, stride uint32) {
+	checkStride(nil, stride)
+	dec.decodeOffset(false)
+}
+
+// DecodeSliceOfStridedBytesContent is the lazy data reader of DecodeSliceOfStridedBytesOffset.
+func DecodeSliceOfStridedBytesContent(dec *Decoder, blobs *[]byte, stride uint32, maxItems uint32) {
+	checkStride(nil, stride)
+	if dec.err != nil {
+		return
+	}
+	// Compute the length of the encoded binaries based on the seen offsets
+	size := dec.retrieveSize()
+	if size%stride != 0 {
+		dec.err = fmt.Errorf("%w: length %d, item size %d", ErrDynamicStaticsIndivisible, size, stride)
+		return
+	}
+	if itemCount := size / stride; itemCount > maxItems {
+		dec.err = fmt.Errorf("%w: decoded %d, max %d", ErrMaxItemsExceeded, itemCount, maxItems)
+		return
+	}
+	// Expand the byte slice if needed and fill it with the data
+	if uint32(cap(*blobs)) < size {
+		*blobs = make([]byte, size)
+	} else {
+		*blobs = (*blobs)[:size]
+	}
+	decodeBytes(dec, *blobs)
+}
+
 // DecodeSliceOfDynamicBytesOffset parses a dynamic slice of dynamic binary blobs.
 func DecodeSliceOfDynamicBytesOffset(dec *Decoder, blobs *[][]byte) {
 	dec.decodeOffset(false)
@@ -334,10 +654,35 @@ func DecodeSliceOfDynamicBytesContent(dec *Decoder, blobs *[][]byte, maxItems ui
 		*blobs = (*blobs)[:items]
 	}
 	for i := uint32(1); i < items; i++ {
-		DecodeDynamicBytesOffset(dec, &(*blobs)[i])
+		dec.decodeOffset(true)
 	}
+	name := dec.field
 	for i := uint32(0); i < items; i++ {
-		DecodeDynamicBytesContent(dec, &(*blobs)[i], maxSize)
+		if DecodeDynamicBytesContent(dec, &(*blobs)[i], maxSize); dec.err != nil {
+			dec.trace(name, int(i))
+			return
+		}
+	}
+}
+
+// DecodeArrayOfStaticObjects parses a static array of static ssz objects.
+//
+// Note, the input slice is assumed to be pre-allocated, but the individual items
+// will be created if nil.
+func DecodeArrayOfStaticObjects[T newableStaticObject[U], U any](dec *Decoder, objects []T) {
+	dec.begin()
+	name := dec.field
+	dec.field = ""
+	for i := range objects {
+		if dec.err != nil {
+			return
+		}
+		if objects[i] == nil {
+			objects[i] = new(U)
+		}
+		if objects[i].DefineSSZ(dec.codec); dec.err != nil {
+			dec.trace(name, i)
+		}
 	}
 }
 
@@ -375,12 +720,14 @@ func DecodeSliceOfStaticObjectsContent[T newableStaticObject[U], U any](dec *Dec
 	} else {
 		*objects = (*objects)[:itemCount]
 	}
+	name := dec.field
+	dec.field = ""
 	for i := uint32(0); i < itemCount; i++ {
 		if (*objects)[i] == nil {
 			(*objects)[i] = new(U)
 		}
-		(*objects)[i].DefineSSZ(dec.codec)
-		if dec.err != nil {
+		if (*objects)[i].DefineSSZ(dec.codec); dec.err != nil {
+			dec.trace(name, int(i))
 			return
 		}
 	}
@@ -434,18 +781,159 @@ func DecodeSliceOfDynamicObjectsContent[T newableDynamicObject[U], U any](dec *D
 		*objects = (*objects)[:items]
 	}
 	for i := uint32(1); i < items; i++ {
-		DecodeDynamicObjectOffset(dec, &(*objects)[i])
+		dec.decodeOffset(true)
+	}
+	name := dec.field
+	for i := uint32(0); i < items; i++ {
+		if DecodeDynamicObjectContent(dec, &(*objects)[i]); dec.err != nil {
+			dec.trace(name, int(i))
+			return
+		}
+	}
+}
+
+// DecodeArrayOfDynamicObjectsOffset parses a static array of dynamic ssz objects.
+func DecodeArrayOfDynamicObjectsOffset[T newableDynamicObject[U], U any](dec *Decoder, objects []T) {
+	dec.decodeOffset(false)
+}
+
+// DecodeArrayOfDynamicObjectsContent is the lazy data reader of DecodeArrayOfDynamicObjectsOffset.
+//
+// Note, the input slice is assumed to be pre-allocated, but the individual items
+// will be created if nil.
+func DecodeArrayOfDynamicObjectsContent[T newableDynamicObject[U], U any](dec *Decoder, objects []T) {
+	if dec.err != nil {
+		return
+	}
+	// Compute the length of the array based on the seen offsets and sanity check
+	// that there's enough data to contain all the item offsets
+	size := dec.retrieveSize()
+	if len(objects) == 0 {
+		if size != 0 {
+			dec.err = fmt.Errorf("%w: decoded %d, type expects %d", ErrFirstOffsetMismatch, size, 0)
+		}
+		return
+	}
+	items := uint32(len(objects))
+	if size < 4*items {
+		dec.err = fmt.Errorf("%w: %d bytes available", ErrShortCounterOffset, size)
+		return
+	}
+	// Descend into a new dynamic list type to track a new sub-length and work
+	// with a fresh set of dynamic offsets
+	dec.descendIntoDynamic(size)
+	defer dec.ascendFromDynamic()
+
+	// Since the number of items is fixed, the first offset must point right after
+	// the offsets of all the items
+	dec.decodeOffset(true)
+	if dec.err != nil {
+		return
+	}
+	if dec.offset != 4*items {
+		dec.err = fmt.Errorf("%w: decoded %d, type expects %d", ErrFirstOffsetMismatch, dec.offset, 4*items)
+		return
+	}
+	for i := uint32(1); i < items; i++ {
+		dec.decodeOffset(true)
 	}
+	name := dec.field
 	for i := uint32(0); i < items; i++ {
-		DecodeDynamicObjectContent(dec, &(*objects)[i])
+		if DecodeDynamicObjectContent(dec, &objects[i]); dec.err != nil {
+			dec.trace(name, int(i))
+			return
+		}
 	}
 }
 
-// decodeOffset decodes the next uint32 as an offset and validates it.
+// DecodeUnionOffset parses a dynamic union.
+func DecodeUnionOffset[S ~uint8](dec *Decoder, selector *S, value *Object) {
+	dec.decodeOffset(false)
+}
+
+// DecodeUnionContent is the lazy data reader of DecodeUnionOffset.
+//
+// The variants are constructors for the union's possible values, indexed by the
+// selector. A nil constructor denotes the None variant, permitted only as the
+// first option.
+func DecodeUnionContent[S ~uint8](dec *Decoder, selector *S, value *Object, variants []func() Object) {
+	if dec.err != nil {
+		return
+	}
+	// Compute the length of the union based on the seen offsets and sanity check
+	// that it contains at least the selector
+	size := dec.retrieveSize()
+	if size == 0 {
+		dec.err = fmt.Errorf("%w: missing selector", ErrInvalidUnionSelector)
+		return
+	}
+	if decodeBytes(dec, dec.buf[:1]); dec.err != nil {
+		return
+	}
+	sel := S(dec.buf[0])
+	if int(sel) >= len(variants) {
+		dec.err = fmt.Errorf("%w: decoded %d, variants %d", ErrInvalidUnionSelector, sel, len(variants))
+		return
+	}
+	// If the None variant was selected, ensure it's the first and it's empty
+	if variants[sel] == nil {
+		if sel != 0 {
+			dec.err = fmt.Errorf("%w: decoded %d, None only permitted at 0", ErrInvalidUnionSelector, sel)
+			return
+		}
+		if size != 1 {
+			dec.err = fmt.Errorf("%w: None variant with %d bytes of data", ErrUnionSizeMismatch, size-1)
+			return
+		}
+		*selector, *value = sel, nil
+		return
+	}
+	// Otherwise create the selected variant and decode into it
+	name := dec.field
+
+	switch obj := variants[sel]().(type) {
+	case StaticObject:
+		if want := obj.SizeSSZ(); size-1 != want {
+			dec.err = fmt.Errorf("%w: decoded %d, variant %d expects %d", ErrUnionSizeMismatch, size-1, sel, want)
+			return
+		}
+		dec.field = ""
+		if obj.DefineSSZ(dec.codec); dec.err != nil {
+			dec.trace(name, -1)
+			return
+		}
+		*selector, *value = sel, obj
+
+	case DynamicObject:
+		// Descend into a new dynamic type to track a new sub-length and work with
+		// a fresh set of dynamic offsets
+		dec.descendIntoDynamic(size - 1)
+		defer dec.ascendFromDynamic()
+
+		dec.field = ""
+		dec.startDynamics(obj.SizeSSZ(true))
+		if obj.DefineSSZ(dec.codec); dec.err != nil {
+			dec.trace(name, -1)
+		}
+		dec.flushDynamics()
+
+		*selector, *value = sel, obj
+
+	default:
+		panic(fmt.Sprintf("unsupported type: %T", obj))
+	}
+}
+
+// decodeOffset decodes the next uint32 as an offset and validates it. The list
+// flag marks the offsets of list items, which are not checked against the start
+// of the dynamic area and are not named after the field being decoded.
 func (dec *Decoder) decodeOffset(list bool) {
 	if dec.err != nil {
 		return
 	}
+	if !list {
+		dec.begin()
+	}
 	var offset uint32
 	if dec.inReader != nil {
 		if _, dec.err = io.ReadFull(dec.inReader, dec.buf[:4]); dec.err != nil {
@@ -453,6 +941,9 @@ func (dec *Decoder) decodeOffset(list bool) {
 		}
 		offset = binary.LittleEndian.Uint32(dec.buf[:4])
 	} else {
+		if dec.short(4) {
+			return
+		}
 		offset = binary.LittleEndian.Uint32(dec.inBuffer)
 		dec.inBuffer = dec.inBuffer[4:]
 	}
@@ -460,16 +951,21 @@ func (dec *Decoder) decodeOffset(list bool) {
 		dec.err = fmt.Errorf("%w: decoded %d, message length %d", ErrOffsetBeyondCapacity, offset, dec.length)
 		return
 	}
-	if dec.offsets == nil && !list && dec.offset != offset {
+	if len(dec.offsets) == 0 && !list && dec.offset != offset {
 		dec.err = fmt.Errorf("%w: decoded %d, type expects %d", ErrFirstOffsetMismatch, offset, dec.offset)
 		return
 	}
-	if dec.offsets != nil && dec.offset > offset {
+	if len(dec.offsets) > 0 && dec.offset > offset {
 		dec.err = fmt.Errorf("%w: decoded %d, previous was %d", ErrBadOffsetProgression, offset, dec.offset)
 		return
 	}
 	dec.offset = offset
 	dec.offsets = append(dec.offsets, offset)
+	if list {
+		dec.names = append(dec.names, "")
+	} else {
+		dec.names = append(dec.names, dec.field)
+	}
 }
 
 // retrieveSize retrieves the length of the nest dynamic item based on the seen
@@ -497,10 +993,19 @@ func (dec *Decoder) retrieveSize() uint32 {
 		}
 		// Nuke out the offsets to avoid leaving junk in the state
 		dec.offsets = dec.offsets[:0]
+
+		// Reverse the field names too, so they can be popped with the sizes
+		for i, j := 0, len(dec.names)-1; i < j; i, j = i+1, j-1 {
+			dec.names[i], dec.names[j] = dec.names[j], dec.names[i]
+		}
 	}
 	// Retrieve the next item's size and pop it off the size stack
 	size := dec.sizes[len(dec.sizes)-1]
 	dec.sizes = dec.sizes[:len(dec.sizes)-1]
+
+	dec.begin()
+	dec.field = dec.names[len(dec.names)-1]
+	dec.names = dec.names[:len(dec.names)-1]
 	return size
 }
 
@@ -537,6 +1042,13 @@ func (dec *Decoder) startDynamics(offset uint32) {
 		dec.sizess = append(dec.sizess, dec.sizes)
 		dec.sizes = nil
 	}
+	if cap(dec.namess) > n {
+		dec.namess = dec.namess[:n+1]
+		dec.names, dec.namess[n] = dec.namess[n], dec.names
+	} else {
+		dec.namess = append(dec.namess, dec.names)
+		dec.names = nil
+	}
 }
 
 // flushDynamics marks the end of the dynamic fields, decoding anything queued up and
@@ -545,10 +1057,133 @@ func (dec *Decoder) flushDynamics() {
 	// Clear out any leftovers from partial dynamic decodes
 	dec.offsets = dec.offsets[:0]
 	dec.sizes = dec.sizes[:0]
+	dec.names = dec.names[:0]
 
 	// Restore the previous state, but swap in the current slice as a future memcache
 	last := len(dec.sizess) - 1
 
 	dec.sizes, dec.sizess[last] = dec.sizess[last], dec.sizes
 	dec.sizess = dec.sizess[:last]
+
+	dec.names, dec.namess[last] = dec.namess[last], dec.names
+	dec.namess = dec.namess[:last]
+}
+
+// short checks whether the input buffer has fewer than size bytes left, setting
+// the decoder's error if so. It must only be called in buffered mode.
+func (dec *Decoder) short(size int) bool {
+	if len(dec.inBuffer) < size {
+		dec.err = &ShortBufferError{Needed: size, Available: len(dec.inBuffer)}
+		return true
+	}
+	return false
+}
+
+// finish checks whether the entire input was consumed by the decoding, setting
+// the decoder's error if some bytes were left over.
+func (dec *Decoder) finish() {
+	if dec.err != nil {
+		return
+	}
+	left := int64(len(dec.inBuffer))
+	if dec.inReader != nil {
+		left = dec.inLimit.N
+	}
+	if left > 0 {
+		dec.err = &DecodeError{
+			Offset: dec.position(),
+			Err:    fmt.Errorf("%w: %d of %d bytes unused", ErrTrailingBytes, left, dec.length),
+		}
+	}
+}
+
+// position returns the absolute offset of the decoder within the entire input.
+func (dec *Decoder) position() int {
+	total := dec.length
+	if len(dec.lengths) > 0 {
+		total = dec.lengths[0]
+	}
+	if dec.inReader != nil {
+		return int(int64(total) - dec.inLimit.N)
+	}
+	return int(total) - len(dec.inBuffer)
+}
+
+// begin marks the start of decoding the next field, taking over the name that
+// was attached to it via Codec.Named, if any. Unnamed fields thus never inherit
+// the name of a preceding one.
+//
+// Optional fields of a StableContainer that are absent are never decoded, so the
+// name attached to them is dropped when the StableContainer moved on by more than
+// the single field the name was meant for.
+func (dec *Decoder) begin() {
+	name, mark, stable := dec.next, &dec.mark, &dec.codec.stable
+	if mark.active != stable.active || mark.bits != stable.bits || stable.index-mark.index > 1 {
+		name = ""
+	}
+	dec.field, dec.next = name, ""
+}
+
+// trace prefixes the field path of the decoder's error with the name of a field
+// (and item index within it, if non-negative), converting the error into a
+// DecodeError rooted at the field being decoded if it is not one yet.
+func (dec *Decoder) trace(name string, index int) {
+	err, ok := dec.err.(*DecodeError)
+	if !ok {
+		err = &DecodeError{Path: dec.field, Offset: dec.position(), Err: dec.err}
+		dec.err = err
+	}
+	if index >= 0 {
+		name = fmt.Sprintf("%s[%d]", name, index)
+	}
+	switch {
+	case name == "":
+	case err.Path == "":
+		err.Path = name
+	case err.Path[0] == '[':
+		err.Path = name + err.Path
+	default:
+		err.Path = name + "." + err.Path
+	}
+}
+
+// decodeBytes parses a static binary blob into a pre-allocated slice.
+func decodeBytes(dec *Decoder, blob []byte) {
+	if dec.inReader != nil {
+		_, dec.err = io.ReadFull(dec.inReader, blob)
+	} else {
+		if dec.short(len(blob)) {
+			return
+		}
+		copy(blob, dec.inBuffer)
+		dec.inBuffer = dec.inBuffer[len(blob):]
+	}
+}
+
+// decodeBools parses a batch of booleans into a pre-allocated slice.
+func decodeBools[T ~bool](dec *Decoder, vs []T) {
+	if dec.inReader == nil && dec.short(len(vs)) {
+		return
+	}
+	for i := range vs {
+		var b byte
+		if dec.inReader != nil {
+			if _, dec.err = io.ReadFull(dec.inReader, dec.buf[:1]); dec.err != nil {
+				return
+			}
+			b = dec.buf[0]
+		} else {
+			b = dec.inBuffer[0]
+			dec.inBuffer = dec.inBuffer[1:]
+		}
+		switch b {
+		case 0:
+			vs[i] = false
+		case 1:
+			vs[i] = true
+		default:
+			dec.err = fmt.Errorf("%w: found %#x", ErrInvalidBoolean, b)
+			return
+		}
+	}
 }
@@ -6,8 +6,8 @@ package ssz
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
-	"unsafe"
 
 	"github.com/holiman/uint256"
 )
@@ -59,6 +59,7 @@ import (
 type Encoder struct {
 	outWriter io.Writer // Underlying output stream to write into (streaming mode)
 	outBuffer []byte    // Underlying output stream to write into (buffered mode)
+	outLength int       // Length of the entire output buffer (buffered mode)
 	err       error     // Any write error to halt future encoding calls
 
 	codec *Codec   // Self-referencing to pass DefineSSZ calls through (API trick)
@@ -67,6 +68,82 @@ type Encoder struct {
 	offset uint32 // Offset tracker for dynamic fields
 }
 
+// EncodeBool serializes a boolean.
+func EncodeBool[T ~bool](enc *Encoder, v T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		if v {
+			enc.buf[0] = 1
+		} else {
+			enc.buf[0] = 0
+		}
+		_, enc.err = enc.outWriter.Write(enc.buf[:1])
+	} else {
+		if enc.short(1) {
+			return
+		}
+		if v {
+			enc.outBuffer[0] = 1
+		} else {
+			enc.outBuffer[0] = 0
+		}
+		enc.outBuffer = enc.outBuffer[1:]
+	}
+}
+
+// EncodeUint8 serializes a uint8.
+func EncodeUint8[T ~uint8](enc *Encoder, n T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		enc.buf[0] = byte(n)
+		_, enc.err = enc.outWriter.Write(enc.buf[:1])
+	} else {
+		if enc.short(1) {
+			return
+		}
+		enc.outBuffer[0] = byte(n)
+		enc.outBuffer = enc.outBuffer[1:]
+	}
+}
+
+// EncodeUint16 serializes a uint16.
+func EncodeUint16[T ~uint16](enc *Encoder, n T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint16(enc.buf[:2], (uint16)(n))
+		_, enc.err = enc.outWriter.Write(enc.buf[:2])
+	} else {
+		if enc.short(2) {
+			return
+		}
+		binary.LittleEndian.PutUint16(enc.outBuffer, (uint16)(n))
+		enc.outBuffer = enc.outBuffer[2:]
+	}
+}
+
+// EncodeUint32 serializes a uint32.
+func EncodeUint32[T ~uint32](enc *Encoder, n T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.buf[:4], (uint32)(n))
+		_, enc.err = enc.outWriter.Write(enc.buf[:4])
+	} else {
+		if enc.short(4) {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.outBuffer, (uint32)(n))
+		enc.outBuffer = enc.outBuffer[4:]
+	}
+}
+
 // EncodeUint64 serializes a uint64.
 func EncodeUint64[T ~uint64](enc *Encoder, n T) {
 	if enc.outWriter != nil {
@@ -76,11 +153,34 @@ func EncodeUint64[T ~uint64](enc *Encoder, n T) {
 		binary.LittleEndian.PutUint64(enc.buf[:8], (uint64)(n))
 		_, enc.err = enc.outWriter.Write(enc.buf[:8])
 	} else {
+		if enc.short(8) {
+			return
+		}
 		binary.LittleEndian.PutUint64(enc.outBuffer, (uint64)(n))
 		enc.outBuffer = enc.outBuffer[8:]
 	}
 }
 
+// EncodeUint128 serializes a uint128, represented as two uint64 limbs in little
+// endian order (least significant first).
+func EncodeUint128[T ~[2]uint64](enc *Encoder, n T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint64(enc.buf[:8], n[0])
+		binary.LittleEndian.PutUint64(enc.buf[8:16], n[1])
+		_, enc.err = enc.outWriter.Write(enc.buf[:16])
+	} else {
+		if enc.short(16) {
+			return
+		}
+		binary.LittleEndian.PutUint64(enc.outBuffer, n[0])
+		binary.LittleEndian.PutUint64(enc.outBuffer[8:], n[1])
+		enc.outBuffer = enc.outBuffer[16:]
+	}
+}
+
 // EncodeUint256 serializes a uint256.
 //
 // Note, a nil pointer is serialized as zero.
@@ -96,6 +196,9 @@ func EncodeUint256(enc *Encoder, n *uint256.Int) {
 			_, enc.err = enc.outWriter.Write([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
 		}
 	} else {
+		if enc.short(32) {
+			return
+		}
 		if n != nil {
 			n.MarshalSSZTo(enc.outBuffer)
 		} else {
@@ -113,11 +216,40 @@ func EncodeStaticBytes(enc *Encoder, blob []byte) {
 		}
 		_, enc.err = enc.outWriter.Write(blob)
 	} else {
+		if enc.short(len(blob)) {
+			return
+		}
 		copy(enc.outBuffer, blob)
 		enc.outBuffer = enc.outBuffer[len(blob):]
 	}
 }
 
+// EncodeBitvector serializes a static bitvector of the given size (in bits).
+//
+// Note, the bits in the last byte above the bitvector's size must be zero, same
+// as the decoder requires, otherwise encoding fails.
+func EncodeBitvector(enc *Encoder, bits []byte, size uint64) {
+	if enc.err != nil {
+		return
+	}
+	// Ensure the unused bits in the last byte are all zero
+	if rem := size & 7; rem != 0 {
+		if junk := bits[len(bits)-1] >> rem; junk != 0 {
+			enc.err = fmt.Errorf("%w: last byte %#x, size %d bits", ErrJunkInBitvector, bits[len(bits)-1], size)
+			return
+		}
+	}
+	if enc.outWriter != nil {
+		_, enc.err = enc.outWriter.Write(bits)
+	} else {
+		if enc.short(len(bits)) {
+			return
+		}
+		copy(enc.outBuffer, bits)
+		enc.outBuffer = enc.outBuffer[len(bits):]
+	}
+}
+
 // EncodeDynamicBytesOffset serializes a dynamic binary blob.
 func EncodeDynamicBytesOffset(enc *Encoder, blob []byte) {
 	if enc.outWriter != nil {
@@ -127,6 +259,9 @@ func EncodeDynamicBytesOffset(enc *Encoder, blob []byte) {
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -141,11 +276,63 @@ func EncodeDynamicBytesContent(enc *Encoder, blob []byte) {
 		}
 		_, enc.err = enc.outWriter.Write(blob)
 	} else {
+		if enc.short(len(blob)) {
+			return
+		}
 		copy(enc.outBuffer, blob)
 		enc.outBuffer = enc.outBuffer[len(blob):]
 	}
 }
 
+// emptyBitlist is the encoding of a bitlist without any bits, only holding the
+// delimiter bit.
+var emptyBitlist = []byte{0x01}
+
+// EncodeBitlistOffset serializes a dynamic bitlist.
+//
+// Note, the bitlist is expected to already contain the delimiter bit, it is the
+// caller's responsibility to maintain it. The only exception is an empty (e.g.
+// nil) slice, which is encoded as the empty bitlist.
+func EncodeBitlistOffset(enc *Encoder, bitlist []byte) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
+		_, enc.err = enc.outWriter.Write(enc.buf[:4])
+	} else {
+		if enc.short(4) {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
+		enc.outBuffer = enc.outBuffer[4:]
+	}
+	enc.offset += SizeBitlist(bitlist)
+}
+
+// EncodeBitlistContent is the lazy data writer for EncodeBitlistOffset.
+func EncodeBitlistContent(enc *Encoder, bitlist []byte) {
+	if enc.err != nil {
+		return
+	}
+	switch {
+	case len(bitlist) == 0:
+		bitlist = emptyBitlist
+	case bitlist[len(bitlist)-1] == 0:
+		enc.err = fmt.Errorf("%w: missing delimiter bit", ErrJunkInBitlist)
+		return
+	}
+	if enc.outWriter != nil {
+		_, enc.err = enc.outWriter.Write(bitlist)
+	} else {
+		if enc.short(len(bitlist)) {
+			return
+		}
+		copy(enc.outBuffer, bitlist)
+		enc.outBuffer = enc.outBuffer[len(bitlist):]
+	}
+}
+
 // EncodeStaticObject serializes a static ssz object.
 func EncodeStaticObject(enc *Encoder, obj StaticObject) {
 	if enc.err != nil {
@@ -163,6 +350,9 @@ func EncodeDynamicObjectOffset(enc *Encoder, obj DynamicObject) {
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -178,6 +368,30 @@ func EncodeDynamicObjectContent(enc *Encoder, obj DynamicObject) {
 	obj.DefineSSZ(enc.codec)
 }
 
+// EncodeArrayOfUint64s serializes a static array of uint64s.
+func EncodeArrayOfUint64s[T ~uint64](enc *Encoder, ns []T) {
+	// Internally this method is essentially calling EncodeUint64 on all numbers
+	// in a loop. Practically, we've inlined that call to make things a *lot*
+	// faster.
+	if enc.outWriter != nil {
+		for _, n := range ns {
+			if enc.err != nil {
+				return
+			}
+			binary.LittleEndian.PutUint64(enc.buf[:8], (uint64)(n))
+			_, enc.err = enc.outWriter.Write(enc.buf[:8])
+		}
+	} else {
+		if enc.short(8 * len(ns)) {
+			return
+		}
+		for _, n := range ns {
+			binary.LittleEndian.PutUint64(enc.outBuffer, (uint64)(n))
+			enc.outBuffer = enc.outBuffer[8:]
+		}
+	}
+}
+
 // EncodeSliceOfUint64sOffset serializes a dynamic slice of uint64s.
 func EncodeSliceOfUint64sOffset[T ~uint64](enc *Encoder, ns []T) {
 	if enc.outWriter != nil {
@@ -187,6 +401,9 @@ func EncodeSliceOfUint64sOffset[T ~uint64](enc *Encoder, ns []T) {
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -206,6 +423,9 @@ func EncodeSliceOfUint64sContent[T ~uint64](enc *Encoder, ns []T) {
 			_, enc.err = enc.outWriter.Write(enc.buf[:8])
 		}
 	} else {
+		if enc.short(8 * len(ns)) {
+			return
+		}
 		for _, n := range ns {
 			binary.LittleEndian.PutUint64(enc.outBuffer, (uint64)(n))
 			enc.outBuffer = enc.outBuffer[8:]
@@ -213,8 +433,90 @@ func EncodeSliceOfUint64sContent[T ~uint64](enc *Encoder, ns []T) {
 	}
 }
 
+// EncodeArrayOfBools serializes a static array of booleans.
+func EncodeArrayOfBools[T ~bool](enc *Encoder, vs []T) {
+	// Internally this method is essentially calling EncodeBool on all the bools
+	// in a loop. Practically, we've inlined that call to make things a *lot*
+	// faster.
+	if enc.outWriter != nil {
+		for _, v := range vs {
+			if enc.err != nil {
+				return
+			}
+			if v {
+				enc.buf[0] = 1
+			} else {
+				enc.buf[0] = 0
+			}
+			_, enc.err = enc.outWriter.Write(enc.buf[:1])
+		}
+	} else {
+		if enc.short(len(vs)) {
+			return
+		}
+		for i, v := range vs {
+			if v {
+				enc.outBuffer[i] = 1
+			} else {
+				enc.outBuffer[i] = 0
+			}
+		}
+		enc.outBuffer = enc.outBuffer[len(vs):]
+	}
+}
+
+// EncodeSliceOfBoolsOffset serializes a dynamic slice of booleans.
+func EncodeSliceOfBoolsOffset[T ~bool](enc *Encoder, vs []T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
+		_, enc.err = enc.outWriter.Write(enc.buf[:4])
+	} else {
+		if enc.short(4) {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
+		enc.outBuffer = enc.outBuffer[4:]
+	}
+	enc.offset += uint32(len(vs))
+}
+
+// EncodeSliceOfBoolsContent is the lazy data writer for EncodeSliceOfBoolsOffset.
+func EncodeSliceOfBoolsContent[T ~bool](enc *Encoder, vs []T) {
+	// Inline:
+	//
+	// EncodeArrayOfBools(enc, vs)
+	if enc.outWriter != nil {
+		for _, v := range vs {
+			if enc.err != nil {
+				return
+			}
+			if v {
+				enc.buf[0] = 1
+			} else {
+				enc.buf[0] = 0
+			}
+			_, enc.err = enc.outWriter.Write(enc.buf[:1])
+		}
+	} else {
+		if enc.short(len(vs)) {
+			return
+		}
+		for i, v := range vs {
+			if v {
+				enc.outBuffer[i] = 1
+			} else {
+				enc.outBuffer[i] = 0
+			}
+		}
+		enc.outBuffer = enc.outBuffer[len(vs):]
+	}
+}
+
 // EncodeArrayOfStaticBytes serializes a static array of static binary blobs.
-func EncodeArrayOfStaticBytes[T commonBinaryLengths](enc *Encoder, blobs []T) {
+func EncodeArrayOfStaticBytes[T staticBinary](enc *Encoder, blobs []T) {
 	// Internally this method is essentially calling EncodeStaticBytes on all
 	// the blobs in a loop. Practically, we've inlined that call to make things
 	// a *lot* faster.
@@ -223,22 +525,22 @@ func EncodeArrayOfStaticBytes[T commonBinaryLengths](enc *Encoder, blobs []T) {
 			if enc.err != nil {
 				return
 			}
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			_, enc.err = enc.outWriter.Write(unsafe.Slice(&blobs[i][0], len(blobs[i])))
+			_, enc.err = enc.outWriter.Write(binaryBytes(&blobs[i]))
 		}
 	} else {
 		for i := 0; i < len(blobs); i++ { // don't range loop, T might be an array, copy is expensive
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			copy(enc.outBuffer, unsafe.Slice(&blobs[i][0], len(blobs[i])))
-			enc.outBuffer = enc.outBuffer[len(blobs[i]):]
+			blob := binaryBytes(&blobs[i])
+			if enc.short(len(blob)) {
+				return
+			}
+			copy(enc.outBuffer, blob)
+			enc.outBuffer = enc.outBuffer[len(blob):]
 		}
 	}
 }
 
 // EncodeSliceOfStaticBytesOffset serializes a dynamic slice of static binary blobs.
-func EncodeSliceOfStaticBytesOffset[T commonBinaryLengths](enc *Encoder, blobs []T) {
+func EncodeSliceOfStaticBytesOffset[T staticBinary](enc *Encoder, blobs []T) {
 	if enc.outWriter != nil {
 		if enc.err != nil {
 			return
@@ -246,6 +548,9 @@ func EncodeSliceOfStaticBytesOffset[T commonBinaryLengths](enc *Encoder, blobs [
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -255,7 +560,7 @@ func EncodeSliceOfStaticBytesOffset[T commonBinaryLengths](enc *Encoder, blobs [
 }
 
 // EncodeSliceOfStaticBytesContent is the lazy data writer for EncodeSliceOfStaticBytesOffset.
-func EncodeSliceOfStaticBytesContent[T commonBinaryLengths](enc *Encoder, blobs []T) {
+func EncodeSliceOfStaticBytesContent[T staticBinary](enc *Encoder, blobs []T) {
 	// Internally this method is essentially calling EncodeStaticBytes on all
 	// the blobs in a loop. Practically, we've inlined that call to make things
 	// a *lot* faster.
@@ -264,20 +569,40 @@ func EncodeSliceOfStaticBytesContent[T commonBinaryLengths](enc *Encoder, blobs
 			if enc.err != nil {
 				return
 			}
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			_, enc.err = enc.outWriter.Write(unsafe.Slice(&blobs[i][0], len(blobs[i])))
+			_, enc.err = enc.outWriter.Write(binaryBytes(&blobs[i]))
 		}
 	} else {
 		for i := 0; i < len(blobs); i++ { // don't range loop, T might be an array, copy is expensive
-			// The code below should have used `blobs[i][:]`, alas Go's generics compiler
-			// is missing that (i.e. a bug): https://github.com/golang/go/issues/51740
-			copy(enc.outBuffer, unsafe.Slice(&blobs[i][0], len(blobs[i])))
-			enc.outBuffer = enc.outBuffer[len(blobs[i]):]
+			blob := binaryBytes(&blobs[i])
+			if enc.short(len(blob)) {
+				return
+			}
+			copy(enc.outBuffer, blob)
+			enc.outBuffer = enc.outBuffer[len(blob):]
 		}
 	}
 }
 
+// EncodeArrayOfStridedBytes serializes a static array of static binary blobs of
+// stride bytes each, flattened into a single byte slice.
+func EncodeArrayOfStridedBytes(enc *Encoder, blobs []byte, stride uint32) {
+	checkStride(blobs, stride)
+	EncodeStaticBytes(enc, blobs)
+}
+
+// EncodeSliceOfStridedBytesOffset serializes a dynamic slice of static binary
+// blobs of stride bytes each, flattened into a single byte slice.
+func EncodeSliceOfStridedBytesOffset(enc *Encoder, blobs []byte, stride uint32) {
+	checkStride(blobs, stride)
+	EncodeDynamicBytesOffset(enc, blobs)
+}
+
+// EncodeSliceOfStridedBytesContent is the lazy data writer for EncodeSliceOfStridedBytesOffset.
+func EncodeSliceOfStridedBytesContent(enc *Encoder, blobs []byte, stride uint32) {
+	checkStride(blobs, stride)
+	EncodeDynamicBytesContent(enc, blobs)
+}
+
 // EncodeSliceOfDynamicBytesOffset serializes a dynamic slice of dynamic binary blobs.
 func EncodeSliceOfDynamicBytesOffset(enc *Encoder, blobs [][]byte) {
 	if enc.outWriter != nil {
@@ -287,6 +612,9 @@ func EncodeSliceOfDynamicBytesOffset(enc *Encoder, blobs [][]byte) {
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -315,6 +643,9 @@ func EncodeSliceOfDynamicBytesContent(enc *Encoder, blobs [][]byte) {
 			enc.offset += uint32(len(blob))
 		}
 	} else {
+		if enc.short(4 * len(blobs)) {
+			return
+		}
 		for _, blob := range blobs {
 			binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 			enc.outBuffer = enc.outBuffer[4:]
@@ -336,12 +667,25 @@ func EncodeSliceOfDynamicBytesContent(enc *Encoder, blobs [][]byte) {
 		}
 	} else {
 		for _, blob := range blobs {
+			if enc.short(len(blob)) {
+				return
+			}
 			copy(enc.outBuffer, blob)
 			enc.outBuffer = enc.outBuffer[len(blob):]
 		}
 	}
 }
 
+// EncodeArrayOfStaticObjects serializes a static array of static ssz objects.
+func EncodeArrayOfStaticObjects[T StaticObject](enc *Encoder, objects []T) {
+	for _, obj := range objects {
+		if enc.err != nil {
+			return
+		}
+		obj.DefineSSZ(enc.codec)
+	}
+}
+
 // EncodeSliceOfStaticObjectsOffset serializes a dynamic slice of static ssz objects.
 func EncodeSliceOfStaticObjectsOffset[T StaticObject](enc *Encoder, objects []T) {
 	if enc.outWriter != nil {
@@ -351,6 +695,9 @@ func EncodeSliceOfStaticObjectsOffset[T StaticObject](enc *Encoder, objects []T)
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -378,6 +725,9 @@ func EncodeSliceOfDynamicObjectsOffset[T DynamicObject](enc *Encoder, objects []
 		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
 		_, enc.err = enc.outWriter.Write(enc.buf[:4])
 	} else {
+		if enc.short(4) {
+			return
+		}
 		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 		enc.outBuffer = enc.outBuffer[4:]
 	}
@@ -406,6 +756,9 @@ func EncodeSliceOfDynamicObjectsContent[T DynamicObject](enc *Encoder, objects [
 			enc.offset += obj.SizeSSZ(false)
 		}
 	} else {
+		if enc.short(4 * len(objects)) {
+			return
+		}
 		for _, obj := range objects {
 			binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
 			enc.outBuffer = enc.outBuffer[4:]
@@ -427,6 +780,168 @@ func EncodeSliceOfDynamicObjectsContent[T DynamicObject](enc *Encoder, objects [
 	}
 }
 
+// EncodeArrayOfDynamicObjectsOffset serializes a static array of dynamic ssz
+// objects. Albeit the number of items is fixed, the array itself is dynamic, so
+// it gets encoded with an offset and its items with further offsets.
+func EncodeArrayOfDynamicObjectsOffset[T DynamicObject](enc *Encoder, objects []T) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
+		_, enc.err = enc.outWriter.Write(enc.buf[:4])
+	} else {
+		if enc.short(4) {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
+		enc.outBuffer = enc.outBuffer[4:]
+	}
+	for _, obj := range objects {
+		enc.offset += 4 + obj.SizeSSZ(false)
+	}
+}
+
+// EncodeArrayOfDynamicObjectsContent is the lazy data writer for EncodeArrayOfDynamicObjectsOffset.
+func EncodeArrayOfDynamicObjectsContent[T DynamicObject](enc *Encoder, objects []T) {
+	enc.offsetDynamics(uint32(4 * len(objects)))
+
+	// Inline:
+	//
+	// 	for _, obj := range objects {
+	//		EncodeDynamicObjectOffset(enc, obj)
+	//	}
+	if enc.outWriter != nil {
+		for _, obj := range objects {
+			if enc.err != nil {
+				return
+			}
+			binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
+			_, enc.err = enc.outWriter.Write(enc.buf[:4])
+
+			enc.offset += obj.SizeSSZ(false)
+		}
+	} else {
+		if enc.short(4 * len(objects)) {
+			return
+		}
+		for _, obj := range objects {
+			binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
+			enc.outBuffer = enc.outBuffer[4:]
+
+			enc.offset += obj.SizeSSZ(false)
+		}
+	}
+	// Inline:
+	//
+	// 	for _, obj := range objects {
+	//		EncodeDynamicObjectContent(enc, obj)
+	//	}
+	for _, obj := range objects {
+		if enc.err != nil {
+			return
+		}
+		enc.offsetDynamics(obj.SizeSSZ(true))
+		obj.DefineSSZ(enc.codec)
+	}
+}
+
+// EncodeUnionOffset serializes a dynamic union.
+func EncodeUnionOffset[S ~uint8](enc *Encoder, selector S, value Object) {
+	if enc.err != nil {
+		return
+	}
+	if enc.err = unionError(selector, value, nil); enc.err != nil {
+		return
+	}
+	if enc.outWriter != nil {
+		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
+		_, enc.err = enc.outWriter.Write(enc.buf[:4])
+	} else {
+		if enc.short(4) {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
+		enc.outBuffer = enc.outBuffer[4:]
+	}
+	enc.offset += SizeUnion(value)
+}
+
+// EncodeUnionContent is the lazy data writer for EncodeUnionOffset.
+//
+// The variants are the constructors for the union's possible values, the same
+// as for decoding. They are only used to validate the selector against the value.
+func EncodeUnionContent[S ~uint8](enc *Encoder, selector S, value Object, variants []func() Object) {
+	if enc.err != nil {
+		return
+	}
+	if enc.err = unionError(selector, value, variants); enc.err != nil {
+		return
+	}
+	EncodeUint8(enc, selector)
+
+	switch v := value.(type) {
+	case nil:
+		// None variant, selector only
+	case StaticObject:
+		EncodeStaticObject(enc, v)
+	case DynamicObject:
+		EncodeDynamicObjectContent(enc, v)
+	default:
+		panic(fmt.Sprintf("unsupported type: %T", value))
+	}
+}
+
+// unionError checks whether a union's selector is consistent with its value and,
+// if known, with its variants, mirroring the checks done during decoding.
+func unionError[S ~uint8](selector S, value Object, variants []func() Object) error {
+	if variants != nil {
+		if int(selector) >= len(variants) {
+			return fmt.Errorf("%w: encoding %d, variants %d", ErrInvalidUnionSelector, selector, len(variants))
+		}
+		if variants[selector] == nil && value != nil {
+			return fmt.Errorf("%w: None variant with %T value", ErrUnionSizeMismatch, value)
+		}
+		if variants[selector] != nil && value == nil {
+			return fmt.Errorf("%w: encoding %d, variant missing value", ErrInvalidUnionSelector, selector)
+		}
+	}
+	if value == nil && selector != 0 {
+		return fmt.Errorf("%w: encoding %d, None only permitted at 0", ErrInvalidUnionSelector, selector)
+	}
+	return nil
+}
+
+// short checks whether the output buffer has fewer than size bytes left, setting
+// the encoder's error if so (or if encoding already failed). It must only be
+// called in buffered mode.
+func (enc *Encoder) short(size int) bool {
+	if enc.err != nil {
+		return true
+	}
+	if len(enc.outBuffer) < size {
+		enc.err = &ShortWriteError{
+			Needed:    enc.outLength - len(enc.outBuffer) + size,
+			Available: enc.outLength,
+			Written:   enc.outLength - len(enc.outBuffer),
+		}
+		return true
+	}
+	return false
+}
+
+// finish checks whether the encoding wrote exactly size bytes into the output
+// buffer, setting the encoder's error if the object's declared size was wrong.
+// It must only be called in buffered mode.
+func (enc *Encoder) finish(size int) {
+	if enc.err != nil {
+		return
+	}
+	if written := enc.outLength - len(enc.outBuffer); written != size {
+		enc.err = fmt.Errorf("%w: declared %d bytes, encoded %d", ErrSizeMismatch, size, written)
+	}
+}
+
 // offsetDynamics marks the item being encoded as a dynamic type, setting the starting
 // offset for the dynamic fields.
 func (enc *Encoder) offsetDynamics(offset uint32) {
@@ -4,7 +4,10 @@
 
 package ssz
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrFirstOffsetMismatch is returned when parsing dynamic types and the first
 // offset (which is supposed to signal the start of the dynamic area) does not
@@ -27,6 +30,73 @@ var ErrMaxLengthExceeded = errors.New("ssz: maximum item size exceeded")
 // type is later than permitted.
 var ErrMaxItemsExceeded = errors.New("ssz: maximum item count exceeded")
 
+// ErrShortBuffer is returned when decoding from a byte buffer that ends before
+// all the fields could be read, or when encoding into a byte buffer too short to
+// hold the object. The actual error is a ShortBufferError or ShortWriteError.
+var ErrShortBuffer = errors.New("ssz: short buffer")
+
+// ShortBufferError is returned when decoding from a byte buffer that ends before
+// all the fields could be read, reporting how many bytes the field being read
+// needed and how many were left. It unwraps to ErrShortBuffer.
+type ShortBufferError struct {
+	Needed    int // Number of bytes needed by the field being read
+	Available int // Number of bytes left in the buffer
+}
+
+// Error implements the error interface.
+func (e *ShortBufferError) Error() string {
+	return fmt.Sprintf("%v: need %d bytes, have %d", ErrShortBuffer, e.Needed, e.Available)
+}
+
+// Unwrap returns ErrShortBuffer, so errors.Is can match the error.
+func (e *ShortBufferError) Unwrap() error {
+	return ErrShortBuffer
+}
+
+// ShortWriteError is returned when encoding into a byte buffer that is too short
+// to hold the object, reporting how many bytes were needed, how many the buffer
+// had and how many were written before giving up. It unwraps to ErrShortBuffer.
+type ShortWriteError struct {
+	Needed    int // Number of bytes needed, at least, to encode the object
+	Available int // Number of bytes available, at most the object's declared size
+	Written   int // Number of bytes written before running out of space
+}
+
+// Error implements the error interface.
+func (e *ShortWriteError) Error() string {
+	return fmt.Sprintf("%v: need %d bytes, have %d, wrote %d", ErrShortBuffer, e.Needed, e.Available, e.Written)
+}
+
+// Unwrap returns ErrShortBuffer, so errors.Is can match the error.
+func (e *ShortWriteError) Unwrap() error {
+	return ErrShortBuffer
+}
+
+// ErrTrailingBytes is returned when an object is decoded, but it does not consume
+// the entire input it was given.
+var ErrTrailingBytes = errors.New("ssz: trailing bytes after object")
+
+// DecodeError is returned when decoding an object fails, reporting where in the
+// input it failed. It unwraps to the underlying error.
+type DecodeError struct {
+	Path   string // Path of the failing field, named via Codec.Named (e.g. body.attestations[17].aggregation_bits)
+	Offset int    // Absolute offset in the input where decoding stopped
+	Err    error  // Underlying error that caused the failure
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%v (offset %d)", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("%v (field %s, offset %d)", e.Err, e.Path, e.Offset)
+}
+
+// Unwrap returns the underlying error, so errors.Is and errors.As can match it.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
 // ErrShortCounterOffset is returned if a counter offset it attempted to be read
 // but there are fewer bytes available on the stream.
 var ErrShortCounterOffset = errors.New("ssz: insufficient data for 4-byte counter offset")
@@ -38,3 +108,42 @@ var ErrBadCounterOffset = errors.New("ssz: counter offset not multiple of 4-byte
 // ErrDynamicStaticsIndivisible is returned when a list of static objects is to
 // be decoded, but the list's total length is not divisible by the item size.
 var ErrDynamicStaticsIndivisible = errors.New("ssz: list of fixed objects not divisible")
+
+// ErrInvalidGeneralizedIndex is returned when a Merkle proof is requested for a
+// generalized index that does not exist within the object's tree.
+var ErrInvalidGeneralizedIndex = errors.New("ssz: invalid generalized index")
+
+// ErrInvalidFieldPath is returned when a field path cannot be resolved into a
+// generalized index within the object's tree.
+var ErrInvalidFieldPath = errors.New("ssz: invalid field path")
+
+// ErrInvalidBoolean is returned when a boolean is decoded, but its encoded value
+// is neither 0x00, nor 0x01.
+var ErrInvalidBoolean = errors.New("ssz: invalid boolean")
+
+// ErrJunkInBitvector is returned when a bitvector is decoded, but the unused bits
+// above its size in the last byte are not zero.
+var ErrJunkInBitvector = errors.New("ssz: non-zero padding bits in bitvector")
+
+// ErrJunkInBitlist is returned when a bitlist is decoded, but it does not have a
+// delimiter bit in its last byte.
+var ErrJunkInBitlist = errors.New("ssz: missing bitlist delimiter")
+
+// ErrInvalidUnionSelector is returned when a union is encoded or decoded, but its
+// selector is out of range, points to a None variant other than the first, or
+// (when encoding) points to a non-None variant without a value.
+var ErrInvalidUnionSelector = errors.New("ssz: invalid union selector")
+
+// ErrUnionSizeMismatch is returned when a union is decoded, but the size of its
+// value does not match the size of the selected variant, or when a union with
+// the None variant selected is encoded with a value.
+var ErrUnionSizeMismatch = errors.New("ssz: union value size mismatch")
+
+// ErrInvalidActiveFields is returned when a StableContainer or Profile is decoded,
+// but its active fields bitvector is malformed or inconsistent with the data.
+var ErrInvalidActiveFields = errors.New("ssz: invalid active fields")
+
+// ErrSizeMismatch is returned when an object is validated or encoded, but its
+// declared size does not match the size derived from its schema or the number
+// of bytes actually encoded.
+var ErrSizeMismatch = errors.New("ssz: declared size mismatch")
@@ -32,6 +32,12 @@ func (w *WithdrawalAsym) DefineSSZ(codec *ssz.Codec) {
 		ssz.DecodeStaticBytes(dec, w.Address[:]) // Field (2) - Address        - 20 bytes
 		ssz.DecodeUint64(dec, &w.Amount)         // Field (3) - Amount         -  8 bytes
 	})
+	codec.DefineHasher(func(has *ssz.Hasher) {
+		ssz.HashUint64(has, w.Index)           // Field (0) - Index          -  8 bytes
+		ssz.HashUint64(has, w.Validator)       // Field (1) - ValidatorIndex -  8 bytes
+		ssz.HashStaticBytes(has, w.Address[:]) // Field (2) - Address        - 20 bytes
+		ssz.HashUint64(has, w.Amount)          // Field (3) - Amount         -  8 bytes
+	})
 }
 
 func ExampleEncodeAsymmetricObject() {
@@ -38,3 +38,11 @@ func ExampleEncodeStaticObject() {
 	// Output:
 	// ssz: 0x0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000
 }
+
+func ExampleHashSequential() {
+	hash := ssz.HashSequential(new(Withdrawal))
+
+	fmt.Printf("hash: %#x\n", hash)
+	// Output:
+	// hash: 0xdb56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71
+}
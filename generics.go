@@ -4,6 +4,11 @@
 
 package ssz
 
+import (
+	"fmt"
+	"unsafe"
+)
+
 // newableStaticObject is a generic type whose purpose is to enforce that the
 // ssz.StaticObject is specifically implemented on a struct pointer. That is
 // needed to allow to instantiate new structs via `new` when parsing.
@@ -20,13 +25,38 @@ type newableDynamicObject[U any] interface {
 	*U
 }
 
-// commonBinaryLengths is a generic type whose purpose is to permit that lists
-// of different fixed-sized binary blobs can be passed to methods.
+// staticBinary is a generic type whose purpose is to permit that lists of
+// different fixed-sized binary blobs can be passed to methods.
+//
+// Go's generics compiler cannot represent arrays of arbitrary sizes with one
+// shorthand notation, so only the common sizes are listed. Blobs of any other
+// size can be flattened into a single byte slice and passed to the strided
+// methods instead (e.g. DefineArrayOfStridedBytes). The sszgen tool mirrors the
+// listed sizes, so keep them in sync (its tests cross-check them).
+type staticBinary interface {
+	// footgun | fork version | address | hash | pubkey | signature | bloom
+	~[]byte | ~[4]byte | ~[20]byte | ~[32]byte | ~[48]byte | ~[96]byte | ~[256]byte
+}
+
+// binaryBytes returns the content of a binary blob as a byte slice, without any
+// copying.
+//
+// Ideally this would be `blob[:]`, alas Go's generics compiler is missing that
+// (i.e. a bug): https://github.com/golang/go/issues/51740
+func binaryBytes[T staticBinary](blob *T) []byte {
+	if len(*blob) == 0 {
+		return nil
+	}
+	return unsafe.Slice(&(*blob)[0], len(*blob))
+}
+
+// checkStride panics if blobs cannot be split into items of stride bytes each.
+// Passing nil blobs (e.g. before decoding into them) only checks the stride.
 //
-// You can add any size to this list really, it's just a limitation of the Go
-// generics compiler that it cannot represent arrays of arbitrary sizes with
-// one shorthand notation.
-type commonBinaryLengths interface {
-	// footgun | address | hash | pubkey
-	~[]byte | ~[20]byte | ~[32]byte | [48]byte
+// Mismatching strides are programming errors the compiler cannot catch, so they
+// are rejected with a panic instead of producing a different encoding.
+func checkStride(blobs []byte, stride uint32) {
+	if stride == 0 || uint32(len(blobs))%stride != 0 {
+		panic(fmt.Sprintf("invalid strided bytes: %d bytes with stride %d", len(blobs), stride))
+	}
 }
@@ -0,0 +1,973 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/holiman/uint256"
+)
+
+// hasherZeroChunk is a 32 byte chunk of all zeroes, used as padding.
+var hasherZeroChunk [32]byte
+
+// hasherZeroCache is a pre-computed table of the roots of all-zero Merkle trees
+// of various depths (i.e. hasherZeroCache[i] is the root of a 2^i leaf tree).
+var hasherZeroCache [65][32]byte
+
+// hasherZeroNodes is the tree counterpart of hasherZeroCache, used to pad the
+// Merkle trees when the hasher is tracking the full tree for proof generation.
+var hasherZeroNodes [65]*hasherNode
+
+func init() {
+	var buf [64]byte
+	for i := 1; i < len(hasherZeroCache); i++ {
+		copy(buf[:32], hasherZeroCache[i-1][:])
+		copy(buf[32:], hasherZeroCache[i-1][:])
+		hasherZeroCache[i] = sha256.Sum256(buf[:])
+	}
+	hasherZeroNodes[0] = new(hasherNode)
+	for i := 1; i < len(hasherZeroNodes); i++ {
+		hasherZeroNodes[i] = &hasherNode{
+			hash:  hasherZeroCache[i],
+			left:  hasherZeroNodes[i-1],
+			right: hasherZeroNodes[i-1],
+		}
+	}
+}
+
+// Hasher is an SSZ Merkle Hash Root computer. It has the following behaviors:
+//
+//  1. The hasher does not hash anything until it has all the data of a layer
+//     (container, vector or list) available, at which point it collapses the
+//     entire layer into a single chunk. This keeps the implementation simple
+//     at the cost of holding a full layer of chunks in memory.
+//
+//  2. Dynamic fields are hashed when their content is defined, but the chunks
+//     are slotted into the position where their offset was defined. The hasher
+//     reserves an empty chunk at offset definition time and fills it in later.
+//     This permits the hasher to use the limits that are defined for decoding
+//     to the content methods.
+//
+//  3. The hasher does not enforce defined size limits on the dynamic fields.
+//     If the caller provided bad data to hash, it is a programming error and
+//     a runtime error will not fix anything.
+type Hasher struct {
+	chunks  [][32]byte    // Scratch space for in-progress hashing chunks
+	layers  []hasherLayer // Stack of layers (containers, lists, etc) in progress
+	pending []int         // Queue of chunk slots reserved for dynamic fields
+
+	tree  bool          // Whether to track the full Merkle tree (proof generation)
+	nodes []*hasherNode // Tree nodes belonging to the chunks (if tracking is enabled)
+	names []hasherName  // Stack of field names defined (if tracking is enabled)
+
+	codec *Codec   // Self-referencing to pass DefineSSZ calls through (API trick)
+	buf   [64]byte // Chunk pair concatenation buffer for hashing
+}
+
+// hasherLayer tracks the state of a single Merkle layer being hashed.
+type hasherLayer struct {
+	chunks  int // Index of the first chunk belonging to this layer
+	pending int // Index of the first reserved dynamic slot in this layer
+	next    int // Index of the next reserved dynamic slot to fill
+	pack    int // Number of items packed into a chunk (0 for composite items)
+
+	progressive bool // Whether the layer is merkleized progressively (EIP-7916)
+	merkleized  bool // Whether the layer's content was merkleized by itself into one chunk
+
+	opaque bool // Whether the layer's object defined a dedicated encoder or decoder
+	hashed bool // Whether the layer's object defined a dedicated hasher
+}
+
+// hasherName tracks a field name defined within a layer, along with the chunk
+// that will hold the field's Merkle root.
+type hasherName struct {
+	layer int    // Index of the layer the field is defined in
+	chunk int    // Index of the chunk the field's root is placed in
+	name  string // Name of the field
+}
+
+// hasherNode is a single node in a Merkle tree, tracked by the hasher only if
+// proof generation was requested, since it's a very allocation heavy process.
+type hasherNode struct {
+	hash  [32]byte    // Merkle root of the subtree
+	left  *hasherNode // Left child of the node (nil for leaves)
+	right *hasherNode // Right child of the node (nil for leaves)
+
+	schema *hasherSchema // Layout of the subtree if it's a container, vector or list
+}
+
+// hasherSchema is the layout of a container, vector or list subtree, needed to
+// resolve field paths to generalized indices.
+type hasherSchema struct {
+	data  *hasherNode // Root of the data subtree (sans any length mix-in)
+	depth int         // Depth of the data subtree
+	limit uint64      // Number of chunks the data subtree can hold
+	pack  int         // Number of items packed into a chunk (0 for composite items)
+	mixin bool        // Whether the data subtree is mixed in with a length
+
+	progressive bool // Whether the data subtree has the progressive layout (EIP-7916)
+
+	names []string // Field names in definition order (nil if not a container)
+}
+
+// HashBool hashes a boolean.
+func HashBool[T ~bool](h *Hasher, v T) {
+	var chunk [32]byte
+	if v {
+		chunk[0] = 1
+	}
+	h.insertChunk(chunk)
+}
+
+// HashUint8 hashes a uint8.
+func HashUint8[T ~uint8](h *Hasher, n T) {
+	var chunk [32]byte
+	chunk[0] = byte(n)
+	h.insertChunk(chunk)
+}
+
+// HashUint16 hashes a uint16.
+func HashUint16[T ~uint16](h *Hasher, n T) {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint16(chunk[:2], (uint16)(n))
+	h.insertChunk(chunk)
+}
+
+// HashUint32 hashes a uint32.
+func HashUint32[T ~uint32](h *Hasher, n T) {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint32(chunk[:4], (uint32)(n))
+	h.insertChunk(chunk)
+}
+
+// HashUint64 hashes a uint64.
+func HashUint64[T ~uint64](h *Hasher, n T) {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint64(chunk[:8], (uint64)(n))
+	h.insertChunk(chunk)
+}
+
+// HashUint128 hashes a uint128, represented as two uint64 limbs in little endian
+// order (least significant first).
+func HashUint128[T ~[2]uint64](h *Hasher, n T) {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint64(chunk[:8], n[0])
+	binary.LittleEndian.PutUint64(chunk[8:16], n[1])
+	h.insertChunk(chunk)
+}
+
+// HashUint256 hashes a uint256.
+//
+// Note, a nil pointer is hashed as zero.
+func HashUint256(h *Hasher, n *uint256.Int) {
+	var chunk [32]byte
+	if n != nil {
+		n.MarshalSSZTo(chunk[:])
+	}
+	h.insertChunk(chunk)
+}
+
+// HashStaticBytes hashes a static binary blob.
+func HashStaticBytes(h *Hasher, blob []byte) {
+	h.hashBytes(blob)
+}
+
+// HashBitvector hashes a static bitvector.
+func HashBitvector(h *Hasher, bits []byte) {
+	h.descendPackedLayer(256)
+	h.insertBlobChunks(bits)
+	h.ascendLayer(0)
+}
+
+// HashDynamicBytesOffset reserves the hash slot of a dynamic binary blob.
+func HashDynamicBytesOffset(h *Hasher, blob []byte) {
+	h.reserveChunk()
+}
+
+// HashDynamicBytesContent is the lazy hasher for HashDynamicBytesOffset.
+func HashDynamicBytesContent(h *Hasher, blob []byte, maxSize uint32) {
+	slot := h.nextReserved()
+
+	h.descendPackedLayer(32)
+	h.insertBlobChunks(blob)
+	h.ascendMixinLayer(uint64(len(blob)), (uint64(maxSize)+31)/32)
+
+	h.fillReserved(slot)
+}
+
+// HashBitlistOffset reserves the hash slot of a dynamic bitlist.
+func HashBitlistOffset(h *Hasher, bitlist []byte) {
+	h.reserveChunk()
+}
+
+// HashBitlistContent is the lazy hasher for HashBitlistOffset.
+//
+// Note, an empty (e.g. nil) slice is hashed as the empty bitlist, whereas one
+// missing the delimiter bit is a programming error and results in a panic.
+func HashBitlistContent(h *Hasher, bitlist []byte, maxBits uint64) {
+	slot := h.nextReserved()
+
+	h.descendPackedLayer(256)
+	size := h.insertBitlistChunks(bitlist)
+	h.ascendMixinLayer(size, (maxBits+255)/256)
+
+	h.fillReserved(slot)
+}
+
+// HashStaticObject hashes a static ssz object.
+func HashStaticObject(h *Hasher, obj StaticObject) {
+	h.descendLayer()
+	obj.DefineSSZ(h.codec)
+	h.ascendLayer(0)
+}
+
+// HashDynamicObjectOffset reserves the hash slot of a dynamic ssz object.
+func HashDynamicObjectOffset(h *Hasher, obj DynamicObject) {
+	h.reserveChunk()
+}
+
+// HashDynamicObjectContent is the lazy hasher for HashDynamicObjectOffset.
+func HashDynamicObjectContent(h *Hasher, obj DynamicObject) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	obj.DefineSSZ(h.codec)
+	h.ascendLayer(0)
+
+	h.fillReserved(slot)
+}
+
+// HashArrayOfUint64s hashes a static array of uint64s.
+func HashArrayOfUint64s[T ~uint64](h *Hasher, ns []T) {
+	h.descendPackedLayer(4)
+	insertUint64Chunks(h, ns)
+	h.ascendLayer(0)
+}
+
+// HashSliceOfUint64sOffset reserves the hash slot of a dynamic slice of uint64s.
+func HashSliceOfUint64sOffset[T ~uint64](h *Hasher, ns []T) {
+	h.reserveChunk()
+}
+
+// HashSliceOfUint64sContent is the lazy hasher for HashSliceOfUint64sOffset.
+func HashSliceOfUint64sContent[T ~uint64](h *Hasher, ns []T, maxItems uint32) {
+	slot := h.nextReserved()
+
+	h.descendPackedLayer(4)
+	insertUint64Chunks(h, ns)
+	h.ascendMixinLayer(uint64(len(ns)), (uint64(maxItems)*8+31)/32)
+
+	h.fillReserved(slot)
+}
+
+// HashArrayOfBools hashes a static array of booleans.
+func HashArrayOfBools[T ~bool](h *Hasher, vs []T) {
+	h.descendPackedLayer(32)
+	insertBoolChunks(h, vs)
+	h.ascendLayer(0)
+}
+
+// HashSliceOfBoolsOffset reserves the hash slot of a dynamic slice of booleans.
+func HashSliceOfBoolsOffset[T ~bool](h *Hasher, vs []T) {
+	h.reserveChunk()
+}
+
+// HashSliceOfBoolsContent is the lazy hasher for HashSliceOfBoolsOffset.
+func HashSliceOfBoolsContent[T ~bool](h *Hasher, vs []T, maxItems uint32) {
+	slot := h.nextReserved()
+
+	h.descendPackedLayer(32)
+	insertBoolChunks(h, vs)
+	h.ascendMixinLayer(uint64(len(vs)), (uint64(maxItems)+31)/32)
+
+	h.fillReserved(slot)
+}
+
+// HashArrayOfStaticBytes hashes a static array of static binary blobs.
+func HashArrayOfStaticBytes[T staticBinary](h *Hasher, blobs []T) {
+	h.descendLayer()
+	for i := 0; i < len(blobs); i++ {
+		h.hashBytes(binaryBytes(&blobs[i]))
+	}
+	h.ascendLayer(0)
+}
+
+// HashSliceOfStaticBytesOffset reserves the hash slot of a dynamic slice of
+// static binary blobs.
+func HashSliceOfStaticBytesOffset[T staticBinary](h *Hasher, blobs []T) {
+	h.reserveChunk()
+}
+
+// HashSliceOfStaticBytesContent is the lazy hasher for HashSliceOfStaticBytesOffset.
+func HashSliceOfStaticBytesContent[T staticBinary](h *Hasher, blobs []T, maxItems uint32) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	for i := 0; i < len(blobs); i++ {
+		h.hashBytes(binaryBytes(&blobs[i]))
+	}
+	h.ascendMixinLayer(uint64(len(blobs)), uint64(maxItems))
+
+	h.fillReserved(slot)
+}
+
+// HashArrayOfStridedBytes hashes a static array of static binary blobs of stride
+// bytes each, flattened into a single byte slice.
+func HashArrayOfStridedBytes(h *Hasher, blobs []byte, stride uint32) {
+	checkStride(blobs, stride)
+
+	h.descendLayer()
+	for i := 0; i < len(blobs); i += int(stride) {
+		h.hashBytes(blobs[i : i+int(stride)])
+	}
+	h.ascendLayer(0)
+}
+
+// HashSliceOfStridedBytesOffset reserves the hash slot of a dynamic slice of
+// static binary blobs of stride bytes each, flattened into a single byte slice.
+func HashSliceOfStridedBytesOffset(h *Hasher, blobs []byte, stride uint32) {
+	checkStride(blobs, stride)
+	h.reserveChunk()
+}
+
+// HashSliceOfStridedBytesContent is the lazy hasher for HashSliceOfStridedBytesOffset.
+func HashSliceOfStridedBytesContent(h *Hasher, blobs []byte, stride uint32, maxItems uint32) {
+	checkStride(blobs, stride)
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	for i := 0; i < len(blobs); i += int(stride) {
+		h.hashBytes(blobs[i : i+int(stride)])
+	}
+	h.ascendMixinLayer(uint64(len(blobs)/int(stride)), uint64(maxItems))
+
+	h.fillReserved(slot)
+}
+
+// HashSliceOfDynamicBytesOffset reserves the hash slot of a dynamic slice of
+// dynamic binary blobs.
+func HashSliceOfDynamicBytesOffset(h *Hasher, blobs [][]byte) {
+	h.reserveChunk()
+}
+
+// HashSliceOfDynamicBytesContent is the lazy hasher for HashSliceOfDynamicBytesOffset.
+func HashSliceOfDynamicBytesContent(h *Hasher, blobs [][]byte, maxItems uint32, maxSize uint32) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	for _, blob := range blobs {
+		h.descendPackedLayer(32)
+		h.insertBlobChunks(blob)
+		h.ascendMixinLayer(uint64(len(blob)), (uint64(maxSize)+31)/32)
+	}
+	h.ascendMixinLayer(uint64(len(blobs)), uint64(maxItems))
+
+	h.fillReserved(slot)
+}
+
+// HashArrayOfStaticObjects hashes a static array of static ssz objects.
+func HashArrayOfStaticObjects[T StaticObject](h *Hasher, objects []T) {
+	h.descendLayer()
+	for _, obj := range objects {
+		h.descendLayer()
+		obj.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	}
+	h.ascendLayer(0)
+}
+
+// HashSliceOfStaticObjectsOffset reserves the hash slot of a dynamic slice of
+// static ssz objects.
+func HashSliceOfStaticObjectsOffset[T StaticObject](h *Hasher, objects []T) {
+	h.reserveChunk()
+}
+
+// HashSliceOfStaticObjectsContent is the lazy hasher for HashSliceOfStaticObjectsOffset.
+func HashSliceOfStaticObjectsContent[T StaticObject](h *Hasher, objects []T, maxItems uint32) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	for _, obj := range objects {
+		h.descendLayer()
+		obj.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	}
+	h.ascendMixinLayer(uint64(len(objects)), uint64(maxItems))
+
+	h.fillReserved(slot)
+}
+
+// HashSliceOfDynamicObjectsOffset reserves the hash slot of a dynamic slice of
+// dynamic ssz objects.
+func HashSliceOfDynamicObjectsOffset[T DynamicObject](h *Hasher, objects []T) {
+	h.reserveChunk()
+}
+
+// HashSliceOfDynamicObjectsContent is the lazy hasher for HashSliceOfDynamicObjectsOffset.
+func HashSliceOfDynamicObjectsContent[T DynamicObject](h *Hasher, objects []T, maxItems uint32) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	for _, obj := range objects {
+		h.descendLayer()
+		obj.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	}
+	h.ascendMixinLayer(uint64(len(objects)), uint64(maxItems))
+
+	h.fillReserved(slot)
+}
+
+// HashArrayOfDynamicObjectsOffset reserves the hash slot of a static array of
+// dynamic ssz objects.
+func HashArrayOfDynamicObjectsOffset[T DynamicObject](h *Hasher, objects []T) {
+	h.reserveChunk()
+}
+
+// HashArrayOfDynamicObjectsContent is the lazy hasher for HashArrayOfDynamicObjectsOffset.
+func HashArrayOfDynamicObjectsContent[T DynamicObject](h *Hasher, objects []T) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	for _, obj := range objects {
+		h.descendLayer()
+		obj.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	}
+	h.ascendLayer(0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveDynamicBytesContent is the lazy hasher for HashDynamicBytesOffset
+// of a progressive binary blob (EIP-7916).
+func HashProgressiveDynamicBytesContent(h *Hasher, blob []byte) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(32)
+	h.insertBlobChunks(blob)
+	h.ascendMixinLayer(uint64(len(blob)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveBitlistContent is the lazy hasher for HashBitlistOffset of a
+// progressive bitlist (EIP-7916).
+//
+// Note, an empty (e.g. nil) slice is hashed as the empty bitlist, whereas one
+// missing the delimiter bit is a programming error and results in a panic.
+func HashProgressiveBitlistContent(h *Hasher, bitlist []byte) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(256)
+	size := h.insertBitlistChunks(bitlist)
+	h.ascendMixinLayer(size, 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveListOfUint64sContent is the lazy hasher for HashSliceOfUint64sOffset
+// of a progressive list (EIP-7916).
+func HashProgressiveListOfUint64sContent[T ~uint64](h *Hasher, ns []T) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(4)
+	insertUint64Chunks(h, ns)
+	h.ascendMixinLayer(uint64(len(ns)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveListOfBoolsContent is the lazy hasher for HashSliceOfBoolsOffset
+// of a progressive list (EIP-7916).
+func HashProgressiveListOfBoolsContent[T ~bool](h *Hasher, vs []T) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(32)
+	insertBoolChunks(h, vs)
+	h.ascendMixinLayer(uint64(len(vs)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveListOfStaticBytesContent is the lazy hasher for
+// HashSliceOfStaticBytesOffset of a progressive list (EIP-7916).
+func HashProgressiveListOfStaticBytesContent[T staticBinary](h *Hasher, blobs []T) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(0)
+	for i := 0; i < len(blobs); i++ {
+		h.hashBytes(binaryBytes(&blobs[i]))
+	}
+	h.ascendMixinLayer(uint64(len(blobs)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveListOfDynamicBytesContent is the lazy hasher for
+// HashSliceOfDynamicBytesOffset of a progressive list (EIP-7916).
+func HashProgressiveListOfDynamicBytesContent(h *Hasher, blobs [][]byte, maxSize uint32) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(0)
+	for _, blob := range blobs {
+		h.descendPackedLayer(32)
+		h.insertBlobChunks(blob)
+		h.ascendMixinLayer(uint64(len(blob)), (uint64(maxSize)+31)/32)
+	}
+	h.ascendMixinLayer(uint64(len(blobs)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveListOfStaticObjectsContent is the lazy hasher for
+// HashSliceOfStaticObjectsOffset of a progressive list (EIP-7916).
+func HashProgressiveListOfStaticObjectsContent[T StaticObject](h *Hasher, objects []T) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(0)
+	for _, obj := range objects {
+		h.descendLayer()
+		obj.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	}
+	h.ascendMixinLayer(uint64(len(objects)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashProgressiveListOfDynamicObjectsContent is the lazy hasher for
+// HashSliceOfDynamicObjectsOffset of a progressive list (EIP-7916).
+func HashProgressiveListOfDynamicObjectsContent[T DynamicObject](h *Hasher, objects []T) {
+	slot := h.nextReserved()
+
+	h.descendProgressiveLayer(0)
+	for _, obj := range objects {
+		h.descendLayer()
+		obj.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	}
+	h.ascendMixinLayer(uint64(len(objects)), 0)
+
+	h.fillReserved(slot)
+}
+
+// HashUnionOffset reserves the hash slot of a dynamic union.
+func HashUnionOffset[S ~uint8](h *Hasher, selector S, value Object) {
+	h.reserveChunk()
+}
+
+// HashUnionContent is the lazy hasher for HashUnionOffset.
+func HashUnionContent[S ~uint8](h *Hasher, selector S, value Object) {
+	slot := h.nextReserved()
+
+	h.descendLayer()
+	switch v := value.(type) {
+	case nil:
+		h.insertChunk([32]byte{}) // None variant
+	case StaticObject:
+		HashStaticObject(h, v)
+	case DynamicObject:
+		h.descendLayer()
+		v.DefineSSZ(h.codec)
+		h.ascendLayer(0)
+	default:
+		panic(fmt.Sprintf("unsupported type: %T", value))
+	}
+	h.ascendMixinLayer(uint64(selector), 0)
+
+	h.fillReserved(slot)
+}
+
+// hashBytes either appends the blob to the hasher's scratch space if it's small
+// enough to fit into a single chunk, or chunks it up and merkleizes it first.
+func (h *Hasher) hashBytes(blob []byte) {
+	if len(blob) <= 32 {
+		var chunk [32]byte
+		copy(chunk[:], blob)
+		h.insertChunk(chunk)
+		return
+	}
+	h.descendPackedLayer(32)
+	h.insertBlobChunks(blob)
+	h.ascendLayer(0)
+}
+
+// insertChunk adds a chunk to the hasher's scratch space.
+func (h *Hasher) insertChunk(chunk [32]byte) {
+	h.chunks = append(h.chunks, chunk)
+	if h.tree {
+		h.nodes = append(h.nodes, &hasherNode{hash: chunk})
+	}
+}
+
+// insertBlobChunks splits a binary blob into 32 byte chunks and adds them to
+// the hasher's scratch space, zero padding the last one if needed.
+func (h *Hasher) insertBlobChunks(blob []byte) {
+	for len(blob) >= 32 {
+		h.insertChunk([32]byte(blob[:32]))
+		blob = blob[32:]
+	}
+	if len(blob) > 0 {
+		var chunk [32]byte
+		copy(chunk[:], blob)
+		h.insertChunk(chunk)
+	}
+}
+
+// insertBoolChunks packs a slice of booleans into 32 byte chunks and adds them
+// to the hasher's scratch space, zero padding the last one if needed.
+func insertBoolChunks[T ~bool](h *Hasher, vs []T) {
+	var chunk [32]byte
+	for i, v := range vs {
+		if v {
+			chunk[i&31] = 1
+		} else {
+			chunk[i&31] = 0
+		}
+		if i&31 == 31 {
+			h.insertChunk(chunk)
+		}
+	}
+	if rem := len(vs) & 31; rem != 0 {
+		clear(chunk[rem:])
+		h.insertChunk(chunk)
+	}
+}
+
+// insertUint64Chunks packs a slice of uint64s into 32 byte chunks and adds them
+// to the hasher's scratch space, zero padding the last one if needed.
+func insertUint64Chunks[T ~uint64](h *Hasher, ns []T) {
+	var chunk [32]byte
+	for i, n := range ns {
+		binary.LittleEndian.PutUint64(chunk[(i&3)<<3:], (uint64)(n))
+		if i&3 == 3 {
+			h.insertChunk(chunk)
+		}
+	}
+	if rem := len(ns) & 3; rem != 0 {
+		clear(chunk[rem<<3:])
+		h.insertChunk(chunk)
+	}
+}
+
+// insertBitlistChunks splits a bitlist into 32 byte chunks without its delimiter
+// bit and adds them to the hasher's scratch space, zero padding the last one if
+// needed. The number of bits in the list is returned.
+func (h *Hasher) insertBitlistChunks(bitlist []byte) uint64 {
+	// Split the bitlist into its full data bytes and the last byte, which contains
+	// the delimiter bit as the most significant set bit
+	var (
+		size uint64
+		last byte
+	)
+	if len(bitlist) > 0 {
+		last = bitlist[len(bitlist)-1]
+		bitlist = bitlist[:len(bitlist)-1]
+
+		msb := bits.Len8(last) - 1
+		if msb < 0 {
+			panic("ssz: bitlist missing delimiter bit")
+		}
+		size = uint64(len(bitlist))<<3 + uint64(msb)
+		last &^= 1 << msb
+	}
+	for len(bitlist) >= 32 {
+		h.insertChunk([32]byte(bitlist[:32]))
+		bitlist = bitlist[32:]
+	}
+	if size&255 != 0 {
+		var chunk [32]byte
+		copy(chunk[:], bitlist)
+		chunk[len(bitlist)] = last
+		h.insertChunk(chunk)
+	}
+	return size
+}
+
+// reserveChunk adds an empty chunk to the hasher's scratch space, tracking it
+// as a slot to be filled in later by a dynamic field's content.
+func (h *Hasher) reserveChunk() {
+	h.pending = append(h.pending, len(h.chunks))
+	h.chunks = append(h.chunks, hasherZeroChunk)
+	if h.tree {
+		h.nodes = append(h.nodes, nil)
+	}
+}
+
+// nextReserved retrieves the position of the next chunk slot to fill in with
+// a dynamic field's content.
+func (h *Hasher) nextReserved() int {
+	layer := &h.layers[len(h.layers)-1]
+
+	slot := h.pending[layer.next]
+	layer.next++
+	return slot
+}
+
+// fillReserved moves the last chunk in the hasher's scratch space into a slot
+// reserved previously for a dynamic field.
+func (h *Hasher) fillReserved(slot int) {
+	h.chunks[slot] = h.chunks[len(h.chunks)-1]
+	h.chunks = h.chunks[:len(h.chunks)-1]
+	if h.tree {
+		h.nodes[slot] = h.nodes[len(h.nodes)-1]
+		h.nodes = h.nodes[:len(h.nodes)-1]
+	}
+}
+
+// descendLayer starts a new Merkle layer (container, vector or list) that will
+// be collapsed into a single chunk when ascending from it.
+func (h *Hasher) descendLayer() {
+	h.layers = append(h.layers, hasherLayer{
+		chunks:  len(h.chunks),
+		pending: len(h.pending),
+		next:    len(h.pending),
+	})
+}
+
+// descendPackedLayer is similar to descendLayer, but the layer will contain
+// basic items packed multiple into a single chunk. This is only relevant for
+// resolving field paths to generalized indices.
+func (h *Hasher) descendPackedLayer(pack int) {
+	h.layers = append(h.layers, hasherLayer{
+		chunks:  len(h.chunks),
+		pending: len(h.pending),
+		next:    len(h.pending),
+		pack:    pack,
+	})
+}
+
+// descendProgressiveLayer is similar to descendPackedLayer, but the layer will
+// be merkleized progressively (EIP-7916) instead of into a balanced tree.
+func (h *Hasher) descendProgressiveLayer(pack int) {
+	h.layers = append(h.layers, hasherLayer{
+		chunks:      len(h.chunks),
+		pending:     len(h.pending),
+		next:        len(h.pending),
+		pack:        pack,
+		progressive: true,
+	})
+}
+
+// markMerkleized flags the current layer as one whose content merkleizes itself
+// into a single chunk (e.g. EIP-7495 containers with their mix-ins), so it will
+// be passed through as is instead of being wrapped into a new subtree.
+func (h *Hasher) markMerkleized() {
+	h.layers[len(h.layers)-1].merkleized = true
+}
+
+// ascendLayer is the counterpart of descendLayer, which merkleizes all the
+// chunks accumulated in the layer, padded up to the given limit (or not at all
+// if the limit is zero). Progressive layers have no limit, it is ignored.
+func (h *Hasher) ascendLayer(limit uint64) {
+	layer := h.layers[len(h.layers)-1]
+	h.layers = h.layers[:len(h.layers)-1]
+
+	if layer.opaque && !layer.hashed {
+		panic("ssz: asymmetric object without dedicated hasher")
+	}
+	h.pending = h.pending[:layer.pending]
+	if !h.tree {
+		if layer.merkleized {
+			return
+		}
+		if layer.progressive {
+			h.merkleizeProgressive(layer.chunks)
+		} else {
+			h.merkleize(layer.chunks, limit)
+		}
+		return
+	}
+	// Tree tracking enabled, gather up the field names of the layer and annotate
+	// the root node with the layout of the subtree
+	count := len(h.chunks) - layer.chunks
+
+	var names []string
+	for len(h.names) > 0 && h.names[len(h.names)-1].layer >= len(h.layers) {
+		name := h.names[len(h.names)-1]
+		h.names = h.names[:len(h.names)-1]
+
+		if index := name.chunk - layer.chunks; index >= 0 && index < count {
+			if names == nil {
+				names = make([]string, count)
+			}
+			names[index] = name.name
+		}
+	}
+	if layer.merkleized {
+		return // keep the content's own layout instead of wrapping it
+	}
+	var depth int
+	if layer.progressive {
+		h.merkleizeProgressive(layer.chunks)
+		limit = 0 // only existing chunks can be descended into
+	} else {
+		depth = h.merkleize(layer.chunks, limit)
+	}
+	root := h.nodes[len(h.nodes)-1]
+	h.nodes[len(h.nodes)-1] = &hasherNode{
+		hash:  root.hash,
+		left:  root.left,
+		right: root.right,
+		schema: &hasherSchema{
+			data:        root,
+			depth:       depth,
+			limit:       max(limit, uint64(count)),
+			pack:        layer.pack,
+			names:       names,
+			progressive: layer.progressive,
+		},
+	}
+}
+
+// ascendMixinLayer is similar to ascendLayer, but also mixes in a length into
+// the final chunk (used for lists).
+func (h *Hasher) ascendMixinLayer(size uint64, limit uint64) {
+	h.ascendLayer(limit)
+
+	var chunk [32]byte
+	binary.LittleEndian.PutUint64(chunk[:8], size)
+	h.insertChunk(chunk)
+
+	h.mixinChunks()
+}
+
+// mixinChunks hashes the last two chunks in the hasher's scratch space together,
+// the first being the root of a data subtree and the second a mix-in for it (e.g.
+// a list's length or a container's active fields).
+func (h *Hasher) mixinChunks() {
+	last := len(h.chunks) - 1
+
+	copy(h.buf[:32], h.chunks[last-1][:])
+	copy(h.buf[32:], h.chunks[last][:])
+	h.chunks[last-1] = sha256.Sum256(h.buf[:])
+	h.chunks = h.chunks[:last]
+
+	if h.tree {
+		data := h.nodes[last-1]
+		h.nodes[last-1] = &hasherNode{
+			hash:   h.chunks[last-1],
+			left:   data,
+			right:  h.nodes[last],
+			schema: data.schema,
+		}
+		h.nodes = h.nodes[:last]
+		if data.schema != nil {
+			data.schema, h.nodes[last-1].schema.mixin = nil, true
+		}
+	}
+}
+
+// merkleize collapses all the chunks starting at a specific index into a single
+// Merkle root, padding the leaves with zero chunks up to the requested limit.
+// The depth of the resulting tree is returned.
+func (h *Hasher) merkleize(start int, limit uint64) int {
+	count := uint64(len(h.chunks) - start)
+	if limit < count {
+		limit = count
+	}
+	var depth int
+	for (uint64(1) << depth) < limit {
+		depth++
+	}
+	if count == 0 {
+		h.chunks = append(h.chunks, hasherZeroCache[depth])
+		if h.tree {
+			h.nodes = append(h.nodes, hasherZeroNodes[depth])
+		}
+		return depth
+	}
+	for level := 0; level < depth; level++ {
+		if count&1 == 1 {
+			h.chunks = append(h.chunks, hasherZeroCache[level])
+			if h.tree {
+				h.nodes = append(h.nodes, hasherZeroNodes[level])
+			}
+			count++
+		}
+		for i := uint64(0); i < count/2; i++ {
+			copy(h.buf[:32], h.chunks[start+int(2*i)][:])
+			copy(h.buf[32:], h.chunks[start+int(2*i+1)][:])
+			h.chunks[start+int(i)] = sha256.Sum256(h.buf[:])
+
+			if h.tree {
+				h.nodes[start+int(i)] = &hasherNode{
+					hash:  h.chunks[start+int(i)],
+					left:  h.nodes[start+int(2*i)],
+					right: h.nodes[start+int(2*i+1)],
+				}
+			}
+		}
+		count /= 2
+		h.chunks = h.chunks[:start+int(count)]
+		if h.tree {
+			h.nodes = h.nodes[:start+int(count)]
+		}
+	}
+	return depth
+}
+
+// merkleizeProgressive collapses all the chunks starting at a specific index into
+// a single Merkle root, using the progressive layout of EIP-7916: the root's right
+// child is a subtree of 1 chunk, its left child's right child is a subtree of the
+// next 4 chunks, and so on with each subtree 4x larger than the previous. The
+// left child of the last subtree is a zero chunk.
+func (h *Hasher) merkleizeProgressive(start int) {
+	count := len(h.chunks) - start
+	if count == 0 {
+		h.chunks = append(h.chunks, hasherZeroChunk)
+		if h.tree {
+			h.nodes = append(h.nodes, hasherZeroNodes[0])
+		}
+		return
+	}
+	// Find the last subtree needed to hold all the chunks
+	offset, size := 0, 1
+	for offset+size < count {
+		offset, size = offset+size, size*4
+	}
+	// Merkleize the subtrees from the last one backwards, each time hashing the
+	// root accumulated so far with the subtree's root
+	var (
+		root = hasherZeroChunk
+		node = hasherZeroNodes[0]
+	)
+	for {
+		h.merkleize(start+offset, uint64(size))
+
+		copy(h.buf[:32], root[:])
+		copy(h.buf[32:], h.chunks[start+offset][:])
+		root = sha256.Sum256(h.buf[:])
+		h.chunks = h.chunks[:start+offset]
+
+		if h.tree {
+			node = &hasherNode{hash: root, left: node, right: h.nodes[start+offset]}
+			h.nodes = h.nodes[:start+offset]
+		}
+		if offset == 0 {
+			break
+		}
+		size /= 4
+		offset -= size
+	}
+	h.chunks = append(h.chunks, root)
+	if h.tree {
+		h.nodes = append(h.nodes, node)
+	}
+}
+
+// reset resets the hasher to an empty state, ready for reuse.
+func (h *Hasher) reset() {
+	h.chunks = h.chunks[:0]
+	h.layers = h.layers[:0]
+	h.pending = h.pending[:0]
+	h.nodes = h.nodes[:0]
+	h.names = h.names[:0]
+}
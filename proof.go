@@ -0,0 +1,286 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ssz
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/bits"
+	"slices"
+)
+
+// Prove generates a Merkle proof for the node at the given generalized index
+// within the object, returning the node's value (leaf) and the sibling hashes
+// along the path to the root, ordered from the bottom of the tree upwards.
+//
+// Note, proof generation needs to construct the entire Merkle tree of the object
+// in memory, so it is significantly slower and more allocation heavy than just
+// hashing it.
+func Prove(obj Object, gindex uint64) (leaf [32]byte, branch [][32]byte, err error) {
+	if gindex == 0 {
+		return [32]byte{}, nil, fmt.Errorf("%w: zero index", ErrInvalidGeneralizedIndex)
+	}
+	depth := bits.Len64(gindex) - 1
+
+	node := hashTree(obj)
+	branch = make([][32]byte, depth)
+	for i := depth - 1; i >= 0; i-- {
+		if node.left == nil {
+			return [32]byte{}, nil, fmt.Errorf("%w: %d deeper than tree", ErrInvalidGeneralizedIndex, gindex)
+		}
+		if gindex&(1<<i) == 0 {
+			branch[i], node = node.right.hash, node.left
+		} else {
+			branch[i], node = node.left.hash, node.right
+		}
+	}
+	return node.hash, branch, nil
+}
+
+// VerifyProof checks whether a Merkle proof generated by Prove is valid for the
+// given root.
+func VerifyProof(root [32]byte, leaf [32]byte, branch [][32]byte, gindex uint64) bool {
+	if gindex == 0 || len(branch) != bits.Len64(gindex)-1 {
+		return false
+	}
+	var buf [64]byte
+	for i := 0; i < len(branch); i++ {
+		if gindex&(1<<i) == 0 {
+			copy(buf[:32], leaf[:])
+			copy(buf[32:], branch[i][:])
+		} else {
+			copy(buf[:32], branch[i][:])
+			copy(buf[32:], leaf[:])
+		}
+		leaf = sha256.Sum256(buf[:])
+	}
+	return leaf == root
+}
+
+// ProveMulti generates a Merkle multiproof for the nodes at the given generalized
+// indices within the object, returning the nodes' values (leaves) and the helper
+// hashes needed to recompute the root, deduplicated and ordered by descending
+// generalized index (as per the consensus specs).
+func ProveMulti(obj Object, gindices []uint64) (leaves [][32]byte, proof [][32]byte, err error) {
+	root := hashTree(obj)
+
+	leaves = make([][32]byte, len(gindices))
+	for i, gindex := range gindices {
+		node, err := root.lookup(gindex)
+		if err != nil {
+			return nil, nil, err
+		}
+		leaves[i] = node.hash
+	}
+	helpers := multiproofHelperIndices(gindices)
+
+	proof = make([][32]byte, len(helpers))
+	for i, gindex := range helpers {
+		node, err := root.lookup(gindex)
+		if err != nil {
+			return nil, nil, err
+		}
+		proof[i] = node.hash
+	}
+	return leaves, proof, nil
+}
+
+// VerifyMulti checks whether a Merkle multiproof generated by ProveMulti is valid
+// for the given root.
+func VerifyMulti(root [32]byte, leaves [][32]byte, proof [][32]byte, gindices []uint64) bool {
+	if len(leaves) != len(gindices) {
+		return false
+	}
+	helpers := multiproofHelperIndices(gindices)
+	if len(proof) != len(helpers) {
+		return false
+	}
+	nodes := make(map[uint64][32]byte, len(leaves)+len(proof))
+	for i, gindex := range gindices {
+		if gindex == 0 {
+			return false
+		}
+		nodes[gindex] = leaves[i]
+	}
+	for i, gindex := range helpers {
+		nodes[gindex] = proof[i]
+	}
+	keys := make([]uint64, 0, len(nodes))
+	for gindex := range nodes {
+		keys = append(keys, gindex)
+	}
+	slices.Sort(keys)
+	slices.Reverse(keys)
+
+	// Walk the known nodes from the bottom up, hashing siblings together until
+	// no more parents can be derived
+	var buf [64]byte
+	for pos := 0; pos < len(keys); pos++ {
+		gindex := keys[pos]
+
+		if _, ok := nodes[gindex/2]; ok || gindex == 1 {
+			continue
+		}
+		sibling, ok := nodes[gindex^1]
+		if !ok {
+			continue
+		}
+		node := nodes[gindex]
+		if gindex&1 == 0 {
+			copy(buf[:32], node[:])
+			copy(buf[32:], sibling[:])
+		} else {
+			copy(buf[:32], sibling[:])
+			copy(buf[32:], node[:])
+		}
+		nodes[gindex/2] = sha256.Sum256(buf[:])
+		keys = append(keys, gindex/2)
+	}
+	have, ok := nodes[1]
+	return ok && have == root
+}
+
+// multiproofHelperIndices returns the generalized indices of all the sibling
+// nodes required to prove a set of nodes, excluding any that can be computed
+// from the proven nodes themselves. The result is sorted in descending order.
+func multiproofHelperIndices(gindices []uint64) []uint64 {
+	var (
+		helpers = make(map[uint64]struct{})
+		paths   = make(map[uint64]struct{})
+	)
+	for _, gindex := range gindices {
+		for ; gindex > 1; gindex /= 2 {
+			helpers[gindex^1] = struct{}{}
+			paths[gindex] = struct{}{}
+		}
+	}
+	indices := make([]uint64, 0, len(helpers))
+	for gindex := range helpers {
+		if _, ok := paths[gindex]; !ok {
+			indices = append(indices, gindex)
+		}
+	}
+	slices.Sort(indices)
+	slices.Reverse(indices)
+	return indices
+}
+
+// GeneralizedIndex resolves a path of field names (strings) and vector or list
+// item indices (integers) into a generalized index within the object's Merkle
+// tree. Container fields may also be referenced by their position in DefineSSZ
+// if they have not been named. Lists' lengths can be referenced via "__len__".
+//
+// Note, path resolution needs to construct the entire Merkle tree of the object
+// in memory, so descending into list items is only possible for existing ones.
+func GeneralizedIndex(obj Object, path ...any) (uint64, error) {
+	var (
+		node   = hashTree(obj)
+		gindex = uint64(1)
+	)
+	for i, elem := range path {
+		schema := node.schema
+		if schema == nil {
+			return 0, fmt.Errorf("%w: %v: not a container, vector or list", ErrInvalidFieldPath, path[:i+1])
+		}
+		// Resolve the path element into an item index within the subtree
+		var index uint64
+		switch elem := elem.(type) {
+		case string:
+			if elem == "__len__" {
+				if !schema.mixin {
+					return 0, fmt.Errorf("%w: %v: not a list", ErrInvalidFieldPath, path[:i+1])
+				}
+				if bits.Len64(gindex) >= 64 {
+					return 0, fmt.Errorf("%w: %v: generalized index overflow", ErrInvalidFieldPath, path[:i+1])
+				}
+				gindex, node = gindex*2+1, node.right
+				continue
+			}
+			index = uint64(slices.Index(schema.names, elem))
+			if elem == "" || index == ^uint64(0) {
+				return 0, fmt.Errorf("%w: %v: unknown field", ErrInvalidFieldPath, path[:i+1])
+			}
+		case int:
+			if elem < 0 {
+				return 0, fmt.Errorf("%w: %v: negative index", ErrInvalidFieldPath, path[:i+1])
+			}
+			index = uint64(elem)
+		case uint64:
+			index = elem
+		default:
+			return 0, fmt.Errorf("%w: %v: unsupported element type %T", ErrInvalidFieldPath, path[:i+1], elem)
+		}
+		// Convert the item index into a chunk index and descend into it
+		chunk := index
+		if schema.pack > 0 {
+			chunk /= uint64(schema.pack)
+		}
+		if chunk >= schema.limit {
+			return 0, fmt.Errorf("%w: %v: index out of bounds", ErrInvalidFieldPath, path[:i+1])
+		}
+		// Progressive subtrees are reached by turning left once for every smaller
+		// subtree, then right, then descending into the subtree itself
+		depth, route := schema.depth, chunk
+		if schema.progressive {
+			offset, size := uint64(0), uint64(1)
+			for chunk >= offset+size {
+				offset, size = offset+size, size*4
+			}
+			sub := bits.Len64(size) - 1
+			depth, route = sub/2+1+sub, 1<<sub|(chunk-offset)
+		}
+		total := depth
+		if schema.mixin {
+			total++
+		}
+		if bits.Len64(gindex)+total > 64 {
+			return 0, fmt.Errorf("%w: %v: generalized index overflow", ErrInvalidFieldPath, path[:i+1])
+		}
+		gindex = gindex<<total | route
+
+		node = schema.data
+		for j := depth - 1; j >= 0; j-- {
+			if route&(1<<j) == 0 {
+				node = node.left
+			} else {
+				node = node.right
+			}
+		}
+	}
+	return gindex, nil
+}
+
+// hashTree computes the full Merkle tree of an ssz object.
+func hashTree(obj Object) *hasherNode {
+	codec := &Codec{has: &Hasher{tree: true}}
+	codec.has.codec = codec
+
+	codec.has.descendLayer()
+	obj.DefineSSZ(codec)
+	codec.has.ascendLayer(0)
+
+	if len(codec.has.nodes) != 1 {
+		panic(fmt.Sprintf("unfinished hashing: left %d nodes", len(codec.has.nodes)))
+	}
+	return codec.has.nodes[0]
+}
+
+// lookup retrieves the node at the given generalized index within the subtree.
+func (n *hasherNode) lookup(gindex uint64) (*hasherNode, error) {
+	if gindex == 0 {
+		return nil, fmt.Errorf("%w: zero index", ErrInvalidGeneralizedIndex)
+	}
+	for i := bits.Len64(gindex) - 2; i >= 0; i-- {
+		if n.left == nil {
+			return nil, fmt.Errorf("%w: %d deeper than tree", ErrInvalidGeneralizedIndex, gindex)
+		}
+		if gindex&(1<<i) == 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,697 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ssz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/holiman/uint256"
+)
+
+// EncodeReflect serializes a plain Go struct (passed by pointer) into a byte
+// buffer, using reflection instead of a DefineSSZ method. The fields are mapped
+// onto ssz types based on their Go types and the ssz-size/ssz-max tags. The buffer
+// must be at least SizeReflect(obj) bytes long, otherwise a ShortWriteError is
+// returned.
+//
+// The output is byte-for-byte the same as encoding a type with a Codec, but the
+// reflection is significantly slower. It is meant for prototyping and tests.
+func EncodeReflect(buf []byte, obj any) error {
+	v := reflectValue(obj)
+	plan := reflectPlanOf(v.Type())
+
+	size := int(plan.sizeOf(v))
+	if len(buf) < size {
+		return &ShortWriteError{Needed: size, Available: len(buf)}
+	}
+	codec := encoderPool.Get().(*Codec)
+	defer encoderPool.Put(codec)
+
+	codec.enc.outBuffer, codec.enc.outLength, codec.enc.err = buf[:size], size, nil
+	if plan.dynamic {
+		codec.enc.offsetDynamics(plan.size)
+	}
+	plan.define(codec, v)
+	codec.enc.finish(size)
+
+	codec.enc.outBuffer = nil
+	return codec.enc.err
+}
+
+// DecodeReflect parses a plain Go struct (passed by pointer) from a byte buffer,
+// using reflection instead of a DefineSSZ method. See EncodeReflect for details.
+func DecodeReflect(blob []byte, obj any) error {
+	v := reflectValue(obj)
+	plan := reflectPlanOf(v.Type())
+
+	codec := decoderPool.Get().(*Codec)
+	defer decoderPool.Put(codec)
+
+	codec.dec.inBuffer, codec.dec.length, codec.dec.limit, codec.dec.err = blob, uint32(len(blob)), DefaultProgressiveLimit, nil
+	codec.dec.field, codec.dec.next = "", ""
+	if plan.dynamic {
+		codec.dec.startDynamics(plan.size)
+		plan.define(codec, v)
+		codec.dec.flushDynamics()
+	} else {
+		plan.define(codec, v)
+	}
+	if codec.dec.finish(); codec.dec.err != nil {
+		codec.dec.trace("", -1)
+	}
+
+	codec.dec.inBuffer = nil
+	return codec.dec.err
+}
+
+// SizeReflect retrieves the size of a plain Go struct (passed by pointer), using
+// reflection instead of a SizeSSZ method. See EncodeReflect for details.
+func SizeReflect(obj any) uint32 {
+	v := reflectValue(obj)
+	return reflectPlanOf(v.Type()).sizeOf(v)
+}
+
+// reflectKind is the ssz type a Go struct field was mapped to.
+type reflectKind int
+
+const (
+	reflectBool reflectKind = iota
+	reflectUint8
+	reflectUint16
+	reflectUint32
+	reflectUint64
+	reflectUint256
+	reflectStaticBytes
+	reflectBitvector
+	reflectArrayOfStaticBytes
+	reflectArrayOfUint64s
+	reflectArrayOfBools
+	reflectStaticObject
+	reflectArrayOfStaticObjects
+
+	// Kinds after this marker are dynamic, split into offsets and contents
+	reflectDynamicBytes
+	reflectBitlist
+	reflectSliceOfBools
+	reflectSliceOfUint64s
+	reflectSliceOfStaticBytes
+	reflectSliceOfDynamicBytes
+	reflectDynamicObject
+	reflectArrayOfDynamicObjects
+	reflectSliceOfStaticObjects
+	reflectSliceOfDynamicObjects
+)
+
+// reflectPlan is the ssz layout of a Go struct type, resolved once from its
+// fields and struct tags, and cached for all subsequent reflection calls.
+type reflectPlan struct {
+	fields  []*reflectField
+	size    uint32 // Size of the static section (fields and offsets)
+	dynamic bool   // Whether the struct has any dynamic fields
+}
+
+// reflectField is the ssz layout of a single struct field.
+type reflectField struct {
+	index    int          // Index of the field within the Go struct
+	kind     reflectKind  // SSZ type the field was mapped to
+	size     uint32       // Size of the field in the static section
+	itemSize uint32       // Size of the items in lists of static binary blobs
+	bits     uint64       // Number of bits in bitvectors (max bits in bitlists)
+	maxItems uint32       // Maximum number of items in dynamic fields
+	maxSize  uint32       // Maximum number of bytes in the items of 2D byte slices
+	plan     *reflectPlan // Layout of the structs in object fields
+}
+
+// uint256Type is the reflected type of the uint256 numbers.
+var uint256Type = reflect.TypeOf(uint256.Int{})
+
+// reflectPlans is the cache of previously resolved struct layouts.
+var reflectPlans sync.Map // map[reflect.Type]*reflectPlan
+
+// reflectPlanOf retrieves the cached ssz layout of a struct type, or resolves
+// it if not yet cached. Any unsupported field type or tag results in a panic,
+// the same way as defining an invalid type via the Codec would.
+func reflectPlanOf(typ reflect.Type) *reflectPlan {
+	if plan, ok := reflectPlans.Load(typ); ok {
+		return plan.(*reflectPlan)
+	}
+	plan, err := newReflectPlan(typ, make(map[reflect.Type]struct{}))
+	if err != nil {
+		panic(fmt.Sprintf("unsupported type: %v: %v", typ, err))
+	}
+	reflectPlans.Store(typ, plan)
+	return plan
+}
+
+// newReflectPlan resolves the ssz layout of a struct type.
+func newReflectPlan(typ reflect.Type, active map[reflect.Type]struct{}) (*reflectPlan, error) {
+	if plan, ok := reflectPlans.Load(typ); ok {
+		return plan.(*reflectPlan), nil
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %v is not a struct", typ)
+	}
+	if _, ok := active[typ]; ok {
+		return nil, fmt.Errorf("type %v is recursive", typ)
+	}
+	active[typ] = struct{}{}
+	defer delete(active, typ)
+
+	plan := new(reflectPlan)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get("ssz") == "-" {
+			continue
+		}
+		f, err := newReflectField(field, active)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		f.index = i
+
+		plan.fields = append(plan.fields, f)
+		plan.size += f.size
+		if f.kind > reflectArrayOfStaticObjects {
+			plan.dynamic = true
+		}
+	}
+	return plan, nil
+}
+
+// newReflectField resolves the ssz layout of a single struct field based on its
+// Go type and the same ssz struct tags used by the code generator.
+func newReflectField(field reflect.StructField, active map[reflect.Type]struct{}) (*reflectField, error) {
+	if field.Anonymous {
+		return nil, fmt.Errorf("embedded fields are not supported")
+	}
+	var bits bool
+	switch kind := field.Tag.Get("ssz"); kind {
+	case "bits":
+		bits = true
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown ssz tag %q", kind)
+	}
+	size, err := parseReflectTag(field.Tag, "ssz-size")
+	if err != nil {
+		return nil, err
+	}
+	limits, err := parseReflectTag(field.Tag, "ssz-max")
+	if err != nil {
+		return nil, err
+	}
+	// Map the Go type onto an ssz type
+	var (
+		typ = field.Type
+		f   = new(reflectField)
+	)
+	switch {
+	case bits && typ.Kind() == reflect.Array && typ.Elem().Kind() == reflect.Uint8:
+		f.kind, f.size, f.bits = reflectBitvector, uint32(typ.Len()), uint64(typ.Len())*8
+		if len(size) == 1 {
+			if size[0] > f.bits || size[0] <= f.bits-8 {
+				return nil, fmt.Errorf("ssz-size tag %d bits mismatches %d byte array", size[0], typ.Len())
+			}
+			f.bits = size[0]
+		}
+		size = nil // bits, already verified
+
+	case bits && typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		f.kind, f.size = reflectBitlist, 4
+
+	case bits:
+		return nil, fmt.Errorf("ssz bits tag on unsupported type %v", typ)
+
+	case typ.Kind() == reflect.Bool:
+		f.kind, f.size = reflectBool, 1
+	case typ.Kind() == reflect.Uint8:
+		f.kind, f.size = reflectUint8, 1
+	case typ.Kind() == reflect.Uint16:
+		f.kind, f.size = reflectUint16, 2
+	case typ.Kind() == reflect.Uint32:
+		f.kind, f.size = reflectUint32, 4
+	case typ.Kind() == reflect.Uint64:
+		f.kind, f.size = reflectUint64, 8
+
+	case typ.Kind() == reflect.Pointer && typ.Elem() == uint256Type:
+		f.kind, f.size = reflectUint256, 32
+
+	case typ.Kind() == reflect.Pointer:
+		if f.plan, err = newReflectPlan(typ.Elem(), active); err != nil {
+			return nil, err
+		}
+		if f.plan.dynamic {
+			f.kind, f.size = reflectDynamicObject, 4
+		} else {
+			f.kind, f.size = reflectStaticObject, f.plan.size
+		}
+	case typ.Kind() == reflect.Array:
+		elem, n := typ.Elem(), uint32(typ.Len())
+		switch {
+		case elem.Kind() == reflect.Uint8:
+			f.kind, f.size = reflectStaticBytes, n
+		case elem.Kind() == reflect.Bool:
+			f.kind, f.size = reflectArrayOfBools, n
+		case elem.Kind() == reflect.Uint64:
+			f.kind, f.size = reflectArrayOfUint64s, n*8
+		case elem.Kind() == reflect.Array && elem.Elem().Kind() == reflect.Uint8:
+			f.kind, f.size = reflectArrayOfStaticBytes, n*uint32(elem.Len())
+		case elem.Kind() == reflect.Pointer && elem.Elem() != uint256Type:
+			if f.plan, err = newReflectPlan(elem.Elem(), active); err != nil {
+				return nil, err
+			}
+			if f.plan.dynamic {
+				f.kind, f.size = reflectArrayOfDynamicObjects, 4
+			} else {
+				f.kind, f.size = reflectArrayOfStaticObjects, n*f.plan.size
+			}
+		default:
+			return nil, fmt.Errorf("unsupported type %v", typ)
+		}
+	case typ.Kind() == reflect.Slice:
+		elem := typ.Elem()
+		switch {
+		case elem.Kind() == reflect.Uint8:
+			f.kind = reflectDynamicBytes
+		case elem.Kind() == reflect.Bool:
+			f.kind = reflectSliceOfBools
+		case elem.Kind() == reflect.Uint64:
+			f.kind = reflectSliceOfUint64s
+		case elem.Kind() == reflect.Array && elem.Elem().Kind() == reflect.Uint8:
+			f.kind, f.itemSize = reflectSliceOfStaticBytes, uint32(elem.Len())
+		case elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.Uint8:
+			f.kind = reflectSliceOfDynamicBytes
+		case elem.Kind() == reflect.Pointer && elem.Elem() != uint256Type:
+			if f.plan, err = newReflectPlan(elem.Elem(), active); err != nil {
+				return nil, err
+			}
+			if f.plan.dynamic {
+				f.kind = reflectSliceOfDynamicObjects
+			} else {
+				f.kind = reflectSliceOfStaticObjects
+			}
+		default:
+			return nil, fmt.Errorf("unsupported type %v", typ)
+		}
+		f.size = 4
+	default:
+		return nil, fmt.Errorf("unsupported type %v", typ)
+	}
+	// Cross check the tags against the resolved layout
+	switch {
+	case f.kind <= reflectArrayOfStaticObjects:
+		if len(limits) != 0 {
+			return nil, fmt.Errorf("ssz-max tag on static field")
+		}
+		if len(size) == 1 && uint64(f.size) != size[0] {
+			return nil, fmt.Errorf("ssz-size tag %d mismatches type size %d", size[0], f.size)
+		}
+	case f.kind == reflectDynamicObject || f.kind == reflectArrayOfDynamicObjects:
+		if len(limits) != 0 {
+			return nil, fmt.Errorf("ssz-max tag on unbounded field")
+		}
+	default:
+		want := 1
+		if f.kind == reflectSliceOfDynamicBytes {
+			want = 2
+		}
+		if len(limits) != want {
+			return nil, fmt.Errorf("ssz-max tag requires %d limit(s), have %d", want, len(limits))
+		}
+		switch f.kind {
+		case reflectDynamicBytes:
+			f.maxSize = uint32(limits[0])
+		case reflectBitlist:
+			f.bits = limits[0]
+		case reflectSliceOfDynamicBytes:
+			f.maxItems, f.maxSize = uint32(limits[0]), uint32(limits[1])
+		default:
+			f.maxItems = uint32(limits[0])
+		}
+	}
+	if len(size) != 0 && f.kind > reflectArrayOfStaticObjects {
+		return nil, fmt.Errorf("ssz-size tag on dynamic field")
+	}
+	return f, nil
+}
+
+// parseReflectTag parses a comma separated list of numbers from a struct tag.
+func parseReflectTag(tag reflect.StructTag, key string) ([]uint64, error) {
+	val, ok := tag.Lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	var nums []uint64
+	for _, part := range strings.Split(val, ",") {
+		num, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s tag %q", key, val)
+		}
+		nums = append(nums, num)
+	}
+	return nums, nil
+}
+
+// sizeOf returns the total size of a struct value.
+func (p *reflectPlan) sizeOf(v reflect.Value) uint32 {
+	size := p.size
+	if p.dynamic {
+		for _, f := range p.fields {
+			if f.kind > reflectArrayOfStaticObjects {
+				size += f.contentSize(v.Field(f.index))
+			}
+		}
+	}
+	return size
+}
+
+// contentSize returns the size of the dynamic content of a field.
+func (f *reflectField) contentSize(v reflect.Value) uint32 {
+	switch f.kind {
+	case reflectDynamicBytes, reflectBitlist, reflectSliceOfBools:
+		return uint32(v.Len())
+	case reflectSliceOfUint64s:
+		return uint32(v.Len()) * 8
+	case reflectSliceOfStaticBytes:
+		return uint32(v.Len()) * f.itemSize
+	case reflectSliceOfStaticObjects:
+		return uint32(v.Len()) * f.plan.size
+	case reflectSliceOfDynamicBytes:
+		var size uint32
+		for i := 0; i < v.Len(); i++ {
+			size += 4 + uint32(v.Index(i).Len())
+		}
+		return size
+	case reflectDynamicObject:
+		return f.plan.sizeOf(reflectDeref(v))
+	case reflectArrayOfDynamicObjects, reflectSliceOfDynamicObjects:
+		var size uint32
+		for i := 0; i < v.Len(); i++ {
+			size += 4 + f.plan.sizeOf(reflectDeref(v.Index(i)))
+		}
+		return size
+	}
+	panic(fmt.Sprintf("unsized field kind: %d", f.kind))
+}
+
+// define runs the codec over all the fields of a struct value. Only encoding
+// and decoding are supported, hashing is not exposed via reflection.
+func (p *reflectPlan) define(c *Codec, v reflect.Value) {
+	for _, f := range p.fields {
+		f.defineStatic(c, v.Field(f.index))
+	}
+	if p.dynamic {
+		for _, f := range p.fields {
+			if f.kind > reflectArrayOfStaticObjects {
+				f.defineContent(c, v.Field(f.index))
+			}
+		}
+	}
+}
+
+// defineStatic runs the codec over a static field or the offset of a dynamic one.
+func (f *reflectField) defineStatic(c *Codec, v reflect.Value) {
+	ptr := v.Addr().UnsafePointer()
+
+	switch f.kind {
+	case reflectBool:
+		DefineBool(c, (*bool)(ptr))
+	case reflectUint8:
+		DefineUint8(c, (*uint8)(ptr))
+	case reflectUint16:
+		DefineUint16(c, (*uint16)(ptr))
+	case reflectUint32:
+		DefineUint32(c, (*uint32)(ptr))
+	case reflectUint64:
+		DefineUint64(c, (*uint64)(ptr))
+	case reflectUint256:
+		DefineUint256(c, (**uint256.Int)(ptr))
+	case reflectStaticBytes, reflectArrayOfStaticBytes:
+		// Arrays of static blobs are encoded as their concatenation, so they can
+		// be handled in one go, without caring about the inner array sizes
+		DefineStaticBytes(c, unsafe.Slice((*byte)(ptr), f.size))
+	case reflectBitvector:
+		DefineBitvector(c, unsafe.Slice((*byte)(ptr), f.size), f.bits)
+	case reflectArrayOfUint64s:
+		DefineArrayOfUint64s(c, unsafe.Slice((*uint64)(ptr), v.Len()))
+	case reflectArrayOfBools:
+		DefineArrayOfBools(c, unsafe.Slice((*bool)(ptr), v.Len()))
+	case reflectStaticObject:
+		f.plan.define(c, reflectObject(c, v))
+	case reflectArrayOfStaticObjects:
+		for i := 0; i < v.Len(); i++ {
+			f.plan.define(c, reflectObject(c, v.Index(i)))
+		}
+	case reflectDynamicBytes:
+		DefineDynamicBytesOffset(c, (*[]byte)(ptr))
+	case reflectBitlist:
+		DefineBitlistOffset(c, (*[]byte)(ptr))
+	case reflectSliceOfBools:
+		DefineSliceOfBoolsOffset(c, (*[]bool)(ptr))
+	case reflectSliceOfUint64s:
+		DefineSliceOfUint64sOffset(c, (*[]uint64)(ptr))
+	case reflectSliceOfDynamicBytes:
+		DefineSliceOfDynamicBytesOffset(c, (*[][]byte)(ptr))
+	default:
+		if c.enc != nil {
+			encodeReflectOffset(c.enc, f.contentSize(v))
+		} else {
+			c.dec.decodeOffset(false)
+		}
+	}
+}
+
+// defineContent runs the codec over the dynamic content of a field.
+func (f *reflectField) defineContent(c *Codec, v reflect.Value) {
+	ptr := v.Addr().UnsafePointer()
+
+	switch f.kind {
+	case reflectDynamicBytes:
+		DefineDynamicBytesContent(c, (*[]byte)(ptr), f.maxSize)
+	case reflectBitlist:
+		DefineBitlistContent(c, (*[]byte)(ptr), f.bits)
+	case reflectSliceOfBools:
+		DefineSliceOfBoolsContent(c, (*[]bool)(ptr), f.maxItems)
+	case reflectSliceOfUint64s:
+		DefineSliceOfUint64sContent(c, (*[]uint64)(ptr), f.maxItems)
+	case reflectSliceOfDynamicBytes:
+		DefineSliceOfDynamicBytesContent(c, (*[][]byte)(ptr), f.maxItems, f.maxSize)
+	case reflectSliceOfStaticBytes:
+		if c.enc != nil {
+			for i := 0; i < v.Len(); i++ {
+				EncodeStaticBytes(c.enc, unsafe.Slice((*byte)(v.Index(i).Addr().UnsafePointer()), f.itemSize))
+			}
+			return
+		}
+		f.decodeStaticItems(c.dec, v)
+	case reflectSliceOfStaticObjects:
+		if c.enc != nil {
+			for i := 0; i < v.Len(); i++ {
+				f.plan.define(c, reflectObject(c, v.Index(i)))
+			}
+			return
+		}
+		f.decodeStaticItems(c.dec, v)
+	case reflectDynamicObject:
+		if c.enc != nil {
+			if c.enc.err != nil {
+				return
+			}
+			c.enc.offsetDynamics(f.plan.size)
+			f.plan.define(c, reflectObject(c, v))
+			return
+		}
+		if c.dec.err != nil {
+			return
+		}
+		c.dec.descendIntoDynamic(c.dec.retrieveSize())
+		defer c.dec.ascendFromDynamic()
+
+		c.dec.startDynamics(f.plan.size)
+		f.plan.define(c, reflectObject(c, v))
+		c.dec.flushDynamics()
+	case reflectArrayOfDynamicObjects, reflectSliceOfDynamicObjects:
+		if c.enc != nil {
+			c.enc.offsetDynamics(uint32(4 * v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				encodeReflectOffset(c.enc, f.plan.sizeOf(reflectDeref(v.Index(i))))
+			}
+			for i := 0; i < v.Len(); i++ {
+				if c.enc.err != nil {
+					return
+				}
+				c.enc.offsetDynamics(f.plan.size)
+				f.plan.define(c, reflectObject(c, v.Index(i)))
+			}
+			return
+		}
+		f.decodeDynamicItems(c.dec, v)
+	}
+}
+
+// decodeStaticItems parses the content of a dynamic list of static items (blobs
+// or objects), mirroring DecodeSliceOfStaticBytesContent and its object variant.
+func (f *reflectField) decodeStaticItems(dec *Decoder, v reflect.Value) {
+	if dec.err != nil {
+		return
+	}
+	size := dec.retrieveSize()
+	if size == 0 {
+		return // empty slice of items
+	}
+	itemSize := f.itemSize
+	if f.plan != nil {
+		itemSize = f.plan.size
+	}
+	if size%itemSize != 0 {
+		dec.err = fmt.Errorf("%w: length %d, item size %d", ErrDynamicStaticsIndivisible, size, itemSize)
+		return
+	}
+	itemCount := size / itemSize
+	if itemCount > f.maxItems {
+		dec.err = fmt.Errorf("%w: decoded %d, max %d", ErrMaxItemsExceeded, itemCount, f.maxItems)
+		return
+	}
+	reflectResize(v, int(itemCount))
+	for i := 0; i < int(itemCount); i++ {
+		if f.plan == nil {
+			DecodeStaticBytes(dec, unsafe.Slice((*byte)(v.Index(i).Addr().UnsafePointer()), itemSize))
+		} else {
+			f.plan.define(dec.codec, reflectObject(dec.codec, v.Index(i)))
+		}
+		if dec.err != nil {
+			return
+		}
+	}
+}
+
+// decodeDynamicItems parses the content of a list or array of dynamic objects,
+// mirroring DecodeSliceOfDynamicObjectsContent and its array variant.
+func (f *reflectField) decodeDynamicItems(dec *Decoder, v reflect.Value) {
+	if dec.err != nil {
+		return
+	}
+	size := dec.retrieveSize()
+	if f.kind == reflectArrayOfDynamicObjects {
+		if v.Len() == 0 {
+			if size != 0 {
+				dec.err = fmt.Errorf("%w: decoded %d, type expects %d", ErrFirstOffsetMismatch, size, 0)
+			}
+			return
+		}
+		if size < 4*uint32(v.Len()) {
+			dec.err = fmt.Errorf("%w: %d bytes available", ErrShortCounterOffset, size)
+			return
+		}
+	} else {
+		if size == 0 {
+			return // empty slice of objects
+		}
+		if size < 4 {
+			dec.err = fmt.Errorf("%w: %d bytes available", ErrShortCounterOffset, size)
+			return
+		}
+	}
+	dec.descendIntoDynamic(size)
+	defer dec.ascendFromDynamic()
+
+	// The first offset doubles as the item counter for lists, and needs to match
+	// the item count for arrays
+	dec.decodeOffset(true)
+	if dec.err != nil {
+		return
+	}
+	items := dec.offset >> 2
+	if f.kind == reflectArrayOfDynamicObjects {
+		if dec.offset != 4*uint32(v.Len()) {
+			dec.err = fmt.Errorf("%w: decoded %d, type expects %d", ErrFirstOffsetMismatch, dec.offset, 4*v.Len())
+			return
+		}
+	} else {
+		if dec.offset&3 != 0 {
+			dec.err = fmt.Errorf("%w: %d bytes", ErrBadCounterOffset, dec.offset)
+			return
+		}
+		if items > f.maxItems {
+			dec.err = fmt.Errorf("%w: decoded %d, max %d", ErrMaxItemsExceeded, items, f.maxItems)
+			return
+		}
+		reflectResize(v, int(items))
+	}
+	for i := uint32(1); i < items; i++ {
+		dec.decodeOffset(false)
+	}
+	for i := 0; i < int(items); i++ {
+		if dec.err != nil {
+			return
+		}
+		dec.descendIntoDynamic(dec.retrieveSize())
+		dec.startDynamics(f.plan.size)
+		f.plan.define(dec.codec, reflectObject(dec.codec, v.Index(i)))
+		dec.flushDynamics()
+		dec.ascendFromDynamic()
+	}
+}
+
+// reflectObject dereferences an object pointer for running the codec on it. When
+// decoding, nil pointers are allocated; when encoding, they are treated as zero.
+func reflectObject(c *Codec, v reflect.Value) reflect.Value {
+	if v.IsNil() && c.dec != nil {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return reflectDeref(v)
+}
+
+// reflectDeref dereferences an object pointer, treating nil as a zero object.
+func reflectDeref(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.New(v.Type().Elem()).Elem()
+	}
+	return v.Elem()
+}
+
+// reflectResize sets the length of a slice, reallocating it if too small.
+func reflectResize(v reflect.Value, n int) {
+	if v.Cap() < n {
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	} else {
+		v.SetLen(n)
+	}
+}
+
+// encodeReflectOffset serializes the offset of a dynamic field with the given
+// content size.
+func encodeReflectOffset(enc *Encoder, size uint32) {
+	if enc.outWriter != nil {
+		if enc.err != nil {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.buf[:4], enc.offset)
+		_, enc.err = enc.outWriter.Write(enc.buf[:4])
+	} else {
+		if enc.short(4) {
+			return
+		}
+		binary.LittleEndian.PutUint32(enc.outBuffer, enc.offset)
+		enc.outBuffer = enc.outBuffer[4:]
+	}
+	enc.offset += size
+}
+
+// reflectValue retrieves the struct behind a pointer passed to the reflection
+// codec, panicking on anything else.
+func reflectValue(obj any) reflect.Value {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("unsupported type: %T", obj))
+	}
+	return v.Elem()
+}
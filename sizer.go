@@ -4,18 +4,382 @@
 
 package ssz
 
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sizer is a dry-run codec that measures the serialized size of an object by
+// running its DefineSSZ schema, without touching any of the data.
+type sizer struct {
+	codec *Codec
+
+	size    uint32 // Bytes taken up by the fields defined so far
+	static  uint32 // Bytes taken up by the static section so far
+	fixed   bool   // Whether only the static section is being measured
+	dynamic bool   // Whether any dynamic field was defined
+	varying bool   // Whether the static section depends on the field values
+	opaque  bool   // Whether any fields were hidden behind an asymmetric definition
+
+	check    bool     // Whether the declared sizes are validated against the derived ones
+	err      error    // First mismatch found between declared and derived sizes
+	path     []string // Field path of the object being measured
+	field    int      // Index of the next field within the object being measured
+	name     string   // Name of the next field, if attached via Codec.Named
+	offsets  []string // Names of the dynamic fields, in order of their offsets
+	contents int      // Index of the next dynamic field to define the content of
+	bound    uint32   // Declared static size of the object being measured
+	culprit  string   // First field found diverging from the declared static size
+}
+
+// sizerLayout is the static layout of an object type, as derived by a sizer.
+type sizerLayout struct {
+	fixed   uint32 // Size of the static section (total size for static objects)
+	dynamic bool   // Whether the object has dynamic fields
+}
+
+// sizerLayouts caches the static layouts of the types whose static section does
+// not depend on the field values.
+var sizerLayouts sync.Map // reflect.Type -> sizerLayout
+
+// deriveLayout runs the schema of an object to retrieve its static layout, or
+// returns it from the cache if the type was already seen.
+func deriveLayout(obj Object) sizerLayout {
+	kind := reflect.TypeOf(obj)
+	if layout, ok := sizerLayouts.Load(kind); ok {
+		return layout.(sizerLayout)
+	}
+	siz := measureObject(obj, true)
+
+	layout := sizerLayout{fixed: siz.size, dynamic: siz.dynamic}
+	if !siz.varying {
+		sizerLayouts.Store(kind, layout)
+	}
+	return layout
+}
+
+// measureObject runs the schema of a top level object with a pooled sizer and
+// returns the final state of the sizer.
+func measureObject(obj Object, fixed bool) sizer {
+	codec := sizerPool.Get().(*Codec)
+	defer sizerPool.Put(codec)
+
+	*codec.siz = sizer{codec: codec, fixed: fixed}
+	obj.DefineSSZ(codec)
+	if codec.siz.opaque {
+		panic(fmt.Sprintf("ssz: cannot derive size of asymmetric type %T", obj))
+	}
+	return *codec.siz
+}
+
+// validateObject runs the schema of a top level object with a pooled sizer in
+// validation mode, returning the first mismatch against the declared sizes.
+func validateObject(obj Object, fixed uint32, total uint32) error {
+	codec := sizerPool.Get().(*Codec)
+	defer sizerPool.Put(codec)
+
+	*codec.siz = sizer{codec: codec, check: true, path: []string{fmt.Sprintf("%T", obj)}}
+	codec.siz.measure(obj, "", fixed, total)
+	return codec.siz.err
+}
+
+// label returns the name of the next field within the object being validated,
+// or its index if it was not named, and moves on to the next field.
+func (siz *sizer) label() string {
+	label := siz.name
+	if label == "" {
+		label = "#" + strconv.Itoa(siz.field)
+	}
+	siz.field, siz.name = siz.field+1, ""
+	return label
+}
+
+// content returns the name of the next dynamic field whose content is defined,
+// as recorded when its offset was defined.
+func (siz *sizer) content() string {
+	if siz.contents >= len(siz.offsets) {
+		return "#?" // content without offset, schema is broken anyway
+	}
+	siz.contents++
+	return siz.offsets[siz.contents-1]
+}
+
+// where returns the full path of a field within the object being validated.
+func (siz *sizer) where(label string) string {
+	return strings.Join(append(siz.path, label), ".")
+}
+
+// track records the first field of the object being validated that ends beyond
+// its declared static size.
+func (siz *sizer) track(label string) {
+	if siz.culprit == "" && siz.static > siz.bound {
+		siz.culprit = siz.where(label)
+	}
+}
+
+// addStatic accounts for a static field of the given size.
+func (siz *sizer) addStatic(size uint32) {
+	siz.size += size
+	siz.static += size
+	if siz.check {
+		siz.track(siz.label())
+	}
+}
+
+// addOffset accounts for the 4 byte offset of a dynamic field.
+func (siz *sizer) addOffset() {
+	siz.size += 4
+	siz.static += 4
+	siz.dynamic = true
+
+	if siz.check {
+		label := siz.label()
+		siz.offsets = append(siz.offsets, label)
+		siz.track(label)
+	}
+}
+
+// addDynamic accounts for the content of a dynamic field, unless only the static
+// section is being measured.
+func (siz *sizer) addDynamic(size uint32) {
+	if !siz.fixed {
+		siz.size += size
+	}
+	if siz.check {
+		siz.content()
+	}
+}
+
+// measure runs the schema of a nested object, returning its total size, or false
+// if it could not be derived due to an asymmetric definition.
+//
+// When validating, the derived sizes are also checked against the declared fixed
+// and total ones, recording the first mismatch.
+func (siz *sizer) measure(obj Object, label string, fixed uint32, total uint32) (uint32, bool) {
+	outer := *siz
+	siz.size, siz.static, siz.fixed, siz.dynamic, siz.varying, siz.opaque = 0, 0, false, false, false, false
+	if siz.check {
+		if label != "" {
+			siz.path = append(siz.path[:len(siz.path):len(siz.path)], label)
+		}
+		siz.field, siz.name, siz.offsets, siz.contents = 0, "", nil, 0
+		siz.bound, siz.culprit = fixed, ""
+	}
+	obj.DefineSSZ(siz.codec)
+
+	// If validation was requested, check the declared sizes
+	if siz.check && !siz.opaque && siz.err == nil {
+		where := strings.Join(siz.path, ".")
+		switch {
+		case siz.varying:
+			// Static section depends on the present fields, only the total is checked
+		case siz.static != fixed:
+			if siz.culprit == "" && len(siz.offsets) > 0 {
+				siz.culprit = siz.where(siz.offsets[0]) // static section too short, first offset is off
+			}
+			siz.err = fmt.Errorf("%w: %s: static size declared %d, derived %d", ErrSizeMismatch, where, fixed, siz.static)
+			if siz.culprit != "" {
+				siz.err = fmt.Errorf("%w, diverging at %s", siz.err, siz.culprit)
+			}
+		}
+		if siz.err == nil && siz.size != total {
+			siz.err = fmt.Errorf("%w: %s: total size declared %d, derived %d", ErrSizeMismatch, where, total, siz.size)
+		}
+	}
+	size, opaque, err := siz.size, siz.opaque, siz.err
+
+	*siz = outer
+	siz.err = err
+	return size, !opaque
+}
+
+// sizeStaticObject accounts for a static object, falling back to its SizeSSZ
+// method if its schema cannot be derived.
+func sizeStaticObject[T StaticObject](siz *sizer, obj T) {
+	var (
+		label    string
+		declared uint32
+	)
+	if siz.check {
+		label, declared = siz.label(), obj.SizeSSZ()
+	}
+	size, ok := siz.measure(obj, label, declared, declared)
+	if !ok {
+		size = obj.SizeSSZ()
+	}
+	siz.size += size
+	siz.static += size
+	if siz.check {
+		siz.track(label)
+	}
+}
+
+// sizeDynamicObjectContent accounts for the content of a dynamic object, falling
+// back to its SizeSSZ method if its schema cannot be derived.
+func sizeDynamicObjectContent[T DynamicObject](siz *sizer, obj T) {
+	var label string
+	if siz.check {
+		label = siz.content()
+	}
+	if !siz.fixed {
+		siz.size += measureDynamicObject(siz, obj, label)
+	}
+}
+
+// measureDynamicObject derives the total size of a nested dynamic object, falling
+// back to its SizeSSZ method if its schema cannot be derived.
+func measureDynamicObject[T DynamicObject](siz *sizer, obj T, label string) uint32 {
+	var fixed, total uint32
+	if siz.check {
+		fixed, total = obj.SizeSSZ(true), obj.SizeSSZ(false)
+	}
+	size, ok := siz.measure(obj, label, fixed, total)
+	if !ok {
+		size = obj.SizeSSZ(false)
+	}
+	return size
+}
+
+// sizeArrayOfStaticObjects accounts for a static array of static objects.
+func sizeArrayOfStaticObjects[T StaticObject](siz *sizer, objects []T) {
+	var label string
+	if siz.check {
+		label = siz.label()
+	}
+	size := measureStaticObjects(siz, objects, label)
+	siz.size += size
+	siz.static += size
+	if siz.check {
+		siz.track(label)
+	}
+}
+
+// measureStaticObjects derives the total size of a list of static objects.
+func measureStaticObjects[T StaticObject](siz *sizer, objects []T, label string) uint32 {
+	if len(objects) == 0 {
+		return 0
+	}
+	// Static objects of the same type are all the same size, measure only one
+	var declared uint32
+	if siz.check {
+		label, declared = label+"[0]", objects[0].SizeSSZ()
+	}
+	size, ok := siz.measure(objects[0], label, declared, declared)
+	if !ok {
+		size = objects[0].SizeSSZ()
+	}
+	return uint32(len(objects)) * size
+}
+
+// sizeSliceOfStaticObjectsContent accounts for the content of a dynamic slice of
+// static objects.
+func sizeSliceOfStaticObjectsContent[T StaticObject](siz *sizer, objects []T) {
+	var label string
+	if siz.check {
+		label = siz.content()
+	}
+	if !siz.fixed {
+		siz.size += measureStaticObjects(siz, objects, label)
+	}
+}
+
+// sizeSliceOfDynamicObjectsContent accounts for the content of a dynamic slice
+// (or static array) of dynamic objects.
+func sizeSliceOfDynamicObjectsContent[T DynamicObject](siz *sizer, objects []T) {
+	var label string
+	if siz.check {
+		label = siz.content()
+	}
+	if siz.fixed {
+		return
+	}
+	for i, obj := range objects {
+		var item string
+		if siz.check {
+			item = label + "[" + strconv.Itoa(i) + "]"
+		}
+		siz.size += 4 + measureDynamicObject(siz, obj, item) // 4-byte offset + dynamic data later
+	}
+}
+
+// sizeUnionContent accounts for the content of a union, with a nil value being
+// the None variant.
+func sizeUnionContent(siz *sizer, value Object) {
+	var label string
+	if siz.check {
+		label = siz.content()
+	}
+	if siz.fixed {
+		return
+	}
+	siz.size++ // selector
+
+	switch v := value.(type) {
+	case nil:
+		// None variant, selector only
+	case StaticObject:
+		var declared uint32
+		if siz.check {
+			declared = v.SizeSSZ()
+		}
+		size, ok := siz.measure(v, label, declared, declared)
+		if !ok {
+			size = v.SizeSSZ()
+		}
+		siz.size += size
+	case DynamicObject:
+		siz.size += measureDynamicObject(siz, v, label)
+	default:
+		panic(fmt.Sprintf("unsupported type: %T", value))
+	}
+}
+
 // SizeDynamicBytes returns the serialized size of the dynamic part of a dynamic
 // blob.
 func SizeDynamicBytes(blobs []byte) uint32 {
 	return uint32(len(blobs))
 }
 
+// SizeBitlist returns the serialized size of the dynamic part of a dynamic
+// bitlist (including the delimiter bit). An empty (e.g. nil) slice is sized as
+// the empty bitlist.
+func SizeBitlist(bitlist []byte) uint32 {
+	if len(bitlist) == 0 {
+		return 1
+	}
+	return uint32(len(bitlist))
+}
+
+// SizeSliceOfBools returns the serialized size of the dynamic part of a dynamic
+// list of booleans.
+func SizeSliceOfBools[T ~bool](vs []T) uint32 {
+	return uint32(len(vs))
+}
+
 // SizeSliceOfUint64s returns the serialized size of the dynamic part of a dynamic
 // list of uint64s.
 func SizeSliceOfUint64s[T ~uint64](ns []T) uint32 {
 	return uint32(len(ns)) * 8
 }
 
+// SizeSliceOfStaticBytes returns the serialized size of the dynamic part of a
+// dynamic list of static blobs.
+func SizeSliceOfStaticBytes[T staticBinary](blobs []T) uint32 {
+	if len(blobs) == 0 {
+		return 0
+	}
+	return uint32(len(blobs) * len(blobs[0]))
+}
+
+// SizeSliceOfStridedBytes returns the serialized size of the dynamic part of a
+// dynamic list of static blobs, flattened into a single byte slice.
+func SizeSliceOfStridedBytes(blobs []byte) uint32 {
+	return uint32(len(blobs))
+}
+
 // SizeDynamicObject returns the serialized size of the dynamic part of a dynamic
 // object.
 func SizeDynamicObject[T DynamicObject](obj T) uint32 {
@@ -50,3 +414,18 @@ func SizeSliceOfDynamicObjects[T DynamicObject](objects []T) uint32 {
 	}
 	return size
 }
+
+// SizeUnion returns the serialized size of the dynamic part of a union, with a
+// nil value being the None variant.
+func SizeUnion(value Object) uint32 {
+	switch v := value.(type) {
+	case nil:
+		return 1
+	case StaticObject:
+		return 1 + v.SizeSSZ()
+	case DynamicObject:
+		return 1 + v.SizeSSZ(false)
+	default:
+		panic(fmt.Sprintf("unsupported type: %T", value))
+	}
+}
@@ -2,17 +2,22 @@
 // Copyright 2024 ssz Authors
 // SPDX-License-Identifier: BSD-3-Clause
 
-// Package ssz is a simplified SSZ encoder/decoder.
+// Package ssz is a simplified SSZ encoder/decoder/hasher.
 package ssz
 
 import (
 	"fmt"
 	"io"
+	"slices"
 	"sync"
 )
 
 // Object defines the methods a type needs to implement to be used as a ssz
 // encodable and decodable object.
+//
+// Top level objects implementing only DefineSSZ have their sizes derived from
+// their schemas (see DeriveSize). Implementing StaticObject or DynamicObject too
+// avoids that overhead, and is required for objects nested in other ones.
 type Object interface {
 	// DefineSSZ defines how an object would be encoded/decoded.
 	DefineSSZ(codec *Codec)
@@ -65,6 +70,26 @@ var decoderPool = sync.Pool{
 	},
 }
 
+// hasherPool is a pool of SSZ hashers to reuse some tiny internal helpers
+// without hitting Go's GC constantly.
+var hasherPool = sync.Pool{
+	New: func() any {
+		codec := &Codec{has: new(Hasher)}
+		codec.has.codec = codec
+		return codec
+	},
+}
+
+// sizerPool is a pool of SSZ sizers to reuse some tiny internal helpers without
+// hitting Go's GC constantly.
+var sizerPool = sync.Pool{
+	New: func() any {
+		codec := &Codec{siz: new(sizer)}
+		codec.siz.codec = codec
+		return codec
+	},
+}
+
 // EncodeToStream serializes the object into a data stream. Do not use this
 // method with a bytes.Buffer to write into a []byte slice, as that will do
 // double the byte copying. For that use case, use EncodeToBytes instead.
@@ -80,7 +105,10 @@ func EncodeToStream(w io.Writer, obj Object) error {
 		codec.enc.offsetDynamics(v.SizeSSZ(true))
 		v.DefineSSZ(codec)
 	default:
-		panic(fmt.Sprintf("unsupported type: %T", obj))
+		if layout := deriveLayout(obj); layout.dynamic {
+			codec.enc.offsetDynamics(layout.fixed)
+		}
+		obj.DefineSSZ(codec)
 	}
 	codec.enc.outWriter = nil
 	return codec.enc.err
@@ -90,11 +118,47 @@ func EncodeToStream(w io.Writer, obj Object) error {
 // if you want to then write the buffer into a stream via some writer, as that
 // would double the memory use for the temporary buffer. For that use case, use
 // EncodeToStream instead.
+//
+// The object is written into the first Size(obj) bytes of the buffer, anything
+// after is left untouched. If the buffer is shorter, a ShortWriteError is
+// returned without writing anything.
 func EncodeToBytes(buf []byte, obj Object) error {
+	size := int(Size(obj))
+	if len(buf) < size {
+		return &ShortWriteError{Needed: size, Available: len(buf)}
+	}
+	return encodeToBytes(buf[:size], obj, size)
+}
+
+// EncodeAppend serializes the object and appends it to dst, growing the slice at
+// most once. It is useful to encode after some prefix (e.g. a message header)
+// without an extra copy. On failure, dst is returned with its original length.
+func EncodeAppend(dst []byte, obj Object) ([]byte, error) {
+	size := int(Size(obj))
+	dst = slices.Grow(dst, size)
+
+	if err := encodeToBytes(dst[len(dst):len(dst)+size], obj, size); err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+size], nil
+}
+
+// Marshal serializes the object into a newly allocated byte slice.
+func Marshal(obj Object) ([]byte, error) {
+	blob, err := EncodeAppend(nil, obj)
+	if err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// encodeToBytes serializes the object into a byte buffer of exactly the size it
+// declared, so objects misreporting their sizes cannot write past it.
+func encodeToBytes(buf []byte, obj Object, size int) error {
 	codec := encoderPool.Get().(*Codec)
 	defer encoderPool.Put(codec)
 
-	codec.enc.outBuffer, codec.enc.err = buf, nil
+	codec.enc.outBuffer, codec.enc.outLength, codec.enc.err = buf, len(buf), nil
 	switch v := obj.(type) {
 	case StaticObject:
 		v.DefineSSZ(codec)
@@ -102,20 +166,42 @@ func EncodeToBytes(buf []byte, obj Object) error {
 		codec.enc.offsetDynamics(v.SizeSSZ(true))
 		v.DefineSSZ(codec)
 	default:
-		panic(fmt.Sprintf("unsupported type: %T", obj))
+		if layout := deriveLayout(obj); layout.dynamic {
+			codec.enc.offsetDynamics(layout.fixed)
+		}
+		obj.DefineSSZ(codec)
 	}
+	codec.enc.finish(size)
+
 	codec.enc.outBuffer = nil
 	return codec.enc.err
 }
 
+// DefaultProgressiveLimit is the maximum number of items (bits for bitlists and
+// bytes for binary blobs) accepted into a progressive list when decoding, since
+// the type itself does not define any limit.
+const DefaultProgressiveLimit = 1 << 24
+
 // DecodeFromStream parses an object with the given size out of a stream. Do not
 // use this method with a bytes.Buffer to read from a []byte slice, as that will
 // double the byte copying. For that use case, use DecodeFromBytes instead.
+//
+// The object must span exactly size bytes: no more than that is read from the
+// stream, and any bytes left unused are reported via ErrTrailingBytes.
 func DecodeFromStream(r io.Reader, obj Object, size uint32) error {
+	return DecodeFromStreamWithLimit(r, obj, size, DefaultProgressiveLimit)
+}
+
+// DecodeFromStreamWithLimit is similar to DecodeFromStream, but caps the number
+// of items accepted into progressive lists to the given value instead of the
+// DefaultProgressiveLimit.
+func DecodeFromStreamWithLimit(r io.Reader, obj Object, size uint32, limit uint32) error {
 	codec := decoderPool.Get().(*Codec)
 	defer decoderPool.Put(codec)
 
-	codec.dec.inReader, codec.dec.length, codec.dec.err = r, size, nil
+	codec.dec.inLimit = io.LimitedReader{R: r, N: int64(size)}
+	codec.dec.inReader, codec.dec.length, codec.dec.limit, codec.dec.err = &codec.dec.inLimit, size, limit, nil
+	codec.dec.field, codec.dec.next = "", ""
 	switch v := obj.(type) {
 	case StaticObject:
 		v.DefineSSZ(codec)
@@ -124,9 +210,19 @@ func DecodeFromStream(r io.Reader, obj Object, size uint32) error {
 		v.DefineSSZ(codec)
 		codec.dec.flushDynamics()
 	default:
-		panic(fmt.Sprintf("unsupported type: %T", obj))
+		if layout := deriveLayout(obj); layout.dynamic {
+			codec.dec.startDynamics(layout.fixed)
+			obj.DefineSSZ(codec)
+			codec.dec.flushDynamics()
+		} else {
+			obj.DefineSSZ(codec)
+		}
+	}
+	if codec.dec.finish(); codec.dec.err != nil {
+		codec.dec.trace("", -1)
 	}
-	codec.dec.inReader = nil
+
+	codec.dec.inReader, codec.dec.inLimit.R = nil, nil
 	return codec.dec.err
 }
 
@@ -134,11 +230,22 @@ func DecodeFromStream(r io.Reader, obj Object, size uint32) error {
 // if you want to first read the buffer from a stream via some reader, as that
 // would double the memory use for the temporary buffer. For that use case, use
 // DecodeFromStream instead.
+//
+// The object must span the entire buffer, any bytes left unused are reported via
+// ErrTrailingBytes.
 func DecodeFromBytes(blob []byte, obj Object) error {
+	return DecodeFromBytesWithLimit(blob, obj, DefaultProgressiveLimit)
+}
+
+// DecodeFromBytesWithLimit is similar to DecodeFromBytes, but caps the number of
+// items accepted into progressive lists to the given value instead of the
+// DefaultProgressiveLimit.
+func DecodeFromBytesWithLimit(blob []byte, obj Object, limit uint32) error {
 	codec := decoderPool.Get().(*Codec)
 	defer decoderPool.Put(codec)
 
-	codec.dec.inBuffer, codec.dec.length, codec.dec.err = blob, uint32(len(blob)), nil
+	codec.dec.inBuffer, codec.dec.length, codec.dec.limit, codec.dec.err = blob, uint32(len(blob)), limit, nil
+	codec.dec.field, codec.dec.next = "", ""
 	switch v := obj.(type) {
 	case StaticObject:
 		v.DefineSSZ(codec)
@@ -147,14 +254,42 @@ func DecodeFromBytes(blob []byte, obj Object) error {
 		v.DefineSSZ(codec)
 		codec.dec.flushDynamics()
 	default:
-		panic(fmt.Sprintf("unsupported type: %T", obj))
+		if layout := deriveLayout(obj); layout.dynamic {
+			codec.dec.startDynamics(layout.fixed)
+			obj.DefineSSZ(codec)
+			codec.dec.flushDynamics()
+		} else {
+			obj.DefineSSZ(codec)
+		}
 	}
+	if codec.dec.finish(); codec.dec.err != nil {
+		codec.dec.trace("", -1)
+	}
+
 	codec.dec.inBuffer = nil
 	return codec.dec.err
 }
 
+// HashSequential computes the ssz merkle root of the object on a single thread.
+// This is useful for processing small objects with stable runtime and O(1) GC
+// guarantees.
+func HashSequential(obj Object) [32]byte {
+	codec := hasherPool.Get().(*Codec)
+	defer hasherPool.Put(codec)
+	defer codec.has.reset()
+
+	codec.has.descendLayer()
+	obj.DefineSSZ(codec)
+	codec.has.ascendLayer(0)
+
+	if len(codec.has.chunks) != 1 {
+		panic(fmt.Sprintf("unfinished hashing: left %d chunks", len(codec.has.chunks)))
+	}
+	return codec.has.chunks[0]
+}
+
 // Size retrieves the size of a ssz object, independent if it's a static or a
-// dynamic one.
+// dynamic one. Objects not implementing SizeSSZ are measured via DeriveSize.
 func Size(obj Object) uint32 {
 	var size uint32
 	switch v := obj.(type) {
@@ -163,7 +298,41 @@ func Size(obj Object) uint32 {
 	case DynamicObject:
 		size = v.SizeSSZ(false)
 	default:
-		panic(fmt.Sprintf("unsupported type: %T", obj))
+		size = DeriveSize(obj, false)
 	}
 	return size
 }
+
+// DeriveSize computes the size of a ssz object by running its DefineSSZ schema
+// in a dry-run mode, returning either the static size of the object if fixed ==
+// true, or the total size otherwise. The object's own SizeSSZ is not consulted,
+// so it is safe to implement SizeSSZ on top of DeriveSize.
+//
+// The static size is cached per type, unless it depends on the field values (as
+// for StableContainers and Profiles). Asymmetric objects cannot be measured and
+// will panic, though nested ones fall back to their own SizeSSZ.
+func DeriveSize(obj Object, fixed bool) uint32 {
+	if fixed {
+		return deriveLayout(obj).fixed
+	}
+	return measureObject(obj, false).size
+}
+
+// Validate runs the DefineSSZ schema of an object in a dry-run mode and checks
+// the declared SizeSSZ methods of it and all its nested objects against the sizes
+// derived from their schemas. The first mismatch is reported with the path of the
+// field where the offset accounting diverges.
+//
+// Validation is meant to be run from tests, not on hot paths. Objects without a
+// SizeSSZ method only have their nested objects validated, and asymmetric ones
+// (nested or not) are trusted as declared.
+func Validate(obj Object) error {
+	switch v := obj.(type) {
+	case StaticObject:
+		return validateObject(obj, v.SizeSSZ(), v.SizeSSZ())
+	case DynamicObject:
+		return validateObject(obj, v.SizeSSZ(true), v.SizeSSZ(false))
+	default:
+		return validateObject(obj, DeriveSize(obj, true), DeriveSize(obj, false))
+	}
+}
@@ -0,0 +1,518 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ssz
+
+import "fmt"
+
+// stableState tracks the field layout of an EIP-7495 StableContainer, Profile or
+// ProgressiveContainer while its fields are being defined.
+type stableState struct {
+	active      bool   // Whether an EIP-7495 container is being defined
+	profile     bool   // Whether the container is a Profile (vs a StableContainer)
+	progressive bool   // Whether the container is a ProgressiveContainer (no optional fields)
+	collect     bool   // Whether the fields are only being collected, not processed
+	capacity    uint64 // Maximum number of fields (N) in the StableContainer
+
+	bits     int  // Offset of the container's bitvectors in the codec's scratch space
+	index    int  // Index of the next field within the (base) StableContainer
+	optional int  // Index of the next optional field within a Profile
+	required bool // Whether the next field is required (Profile only)
+
+	fixed   uint32 // Static size of the present fields (offsets and static values)
+	dynamic bool   // Whether any present field is dynamic
+}
+
+// DefineStableContainer defines the object as an EIP-7495 StableContainer with
+// the given maximum capacity of fields. The fields callback needs to define all
+// the fields of the container via the DefineOptional* methods, in order.
+//
+// The container is serialized as an active fields bitvector followed by all the
+// present fields. Its merkle root is computed as if it had all N fields, mixed
+// in with the active fields bitvector.
+//
+// Note, the fields callback will be invoked multiple times (to gather the layout
+// before the actual processing), so it must not have side effects.
+func DefineStableContainer(c *Codec, capacity uint64, fields func(c *Codec)) {
+	defineStable(c, false, capacity, fields)
+}
+
+// DefineProfile defines the object as an EIP-7495 Profile of a StableContainer
+// with the given maximum capacity of fields. The fields callback needs to define
+// the fields of the Profile in the order of the base StableContainer, via the
+// DefineOptional* methods. Mandatory fields need to be marked with Required, and
+// fields of the base container not part of the Profile need to be passed over
+// with Skip.
+//
+// The Profile is serialized as a bitvector of its present optional fields (only
+// if there are any optional fields), followed by all the present fields. Its
+// merkle root is the same as its base StableContainer's.
+//
+// Note, the fields callback will be invoked multiple times (to gather the layout
+// before the actual processing), so it must not have side effects.
+func DefineProfile(c *Codec, capacity uint64, fields func(c *Codec)) {
+	defineStable(c, true, capacity, fields)
+}
+
+// Required marks the next field of a Profile as mandatory, returning the codec
+// itself so it can be inlined into the field definition. Required fields must
+// always be present and are not tracked in the Profile's optional bitvector.
+func (c *Codec) Required() *Codec {
+	if !c.stable.active || !c.stable.profile {
+		panic("ssz: required field outside of profile")
+	}
+	c.stable.required = true
+	return c
+}
+
+// Skip passes over the given number of fields of the base StableContainer that
+// are not part of the Profile being defined (or over inactive fields of a
+// ProgressiveContainer), returning the codec itself so it can be inlined into
+// the next field definition.
+func (c *Codec) Skip(fields int) *Codec {
+	if !c.stable.active {
+		panic("ssz: skipped fields outside of stable container")
+	}
+	if c.has != nil && !c.stable.collect {
+		for i := 0; i < fields; i++ {
+			c.has.insertChunk([32]byte{})
+		}
+	}
+	c.stable.index += fields
+	return c
+}
+
+// DefineProgressiveContainer defines the object as an EIP-7495 ProgressiveContainer
+// with the given active fields bitvector (at most 256 bits, the last one set).
+// The fields callback needs to define all the active fields of the container in
+// order, passing over the inactive ones with Skip.
+//
+// The container is serialized the same way as a plain container. Its merkle root
+// is computed progressively (EIP-7916) over the field roots (zero for inactive
+// fields), mixed in with the active fields bitvector.
+func DefineProgressiveContainer(c *Codec, active []byte, fields func(c *Codec)) {
+	if len(active) == 0 || len(active) > 32 || active[len(active)-1] == 0 {
+		panic(fmt.Sprintf("ssz: invalid progressive container active fields: %x", active))
+	}
+	outer := c.stable
+	defer func() { c.stable = outer }()
+
+	c.stable = stableState{active: true, progressive: true}
+	if c.has == nil {
+		fields(c)
+		return
+	}
+	c.has.markMerkleized()
+
+	c.has.descendProgressiveLayer(0)
+	fields(c)
+	c.has.ascendLayer(0)
+
+	var chunk [32]byte
+	copy(chunk[:], active)
+	c.has.insertChunk(chunk)
+
+	c.has.mixinChunks()
+}
+
+// defineStable is the shared implementation of DefineStableContainer and of
+// DefineProfile.
+func defineStable(c *Codec, profile bool, capacity uint64, fields func(c *Codec)) {
+	// Save any outer container state and allocate the scratch space for the new
+	// container's active and present bitvectors
+	outer := c.stable
+	defer func() { c.stable = outer }()
+
+	size := int(capacity+7) / 8
+	start := len(c.stableBits)
+	for i := 0; i < 2*size; i++ {
+		c.stableBits = append(c.stableBits, 0)
+	}
+	defer func() { c.stableBits = c.stableBits[:start] }()
+
+	c.stable = stableState{active: true, profile: profile, capacity: capacity, bits: start}
+
+	switch {
+	case c.enc != nil:
+		encodeStable(c, fields, size)
+	case c.dec != nil:
+		decodeStable(c, fields, size)
+	case c.siz != nil:
+		sizeStable(c, fields, size)
+	default:
+		hashStable(c, fields, size)
+	}
+}
+
+// encodeStable serializes a StableContainer or Profile.
+func encodeStable(c *Codec, fields func(c *Codec), size int) {
+	// Collect the present fields and their static sizes
+	c.stable.collect = true
+	fields(c)
+	c.stable.collect = false
+
+	// Write out the bitvector prefix, offsetting the dynamic fields after it
+	if c.stable.profile {
+		EncodeStaticBytes(c.enc, c.stableBits[c.stable.bits+size:][:(c.stable.optional+7)/8])
+	} else {
+		EncodeStaticBytes(c.enc, c.stableBits[c.stable.bits:][:size])
+	}
+	c.enc.offsetDynamics(c.stable.fixed)
+
+	c.stable.index, c.stable.optional = 0, 0
+	fields(c)
+}
+
+// decodeStable parses a StableContainer or Profile.
+func decodeStable(c *Codec, fields func(c *Codec), size int) {
+	dec := c.dec
+	if dec.err != nil {
+		return
+	}
+	// Profiles only have a bitvector for their optional fields, so count them in
+	// order to know how much data to read
+	var (
+		bits  = c.stableBits[c.stable.bits:][:size]
+		count = int(c.stable.capacity)
+	)
+	if c.stable.profile {
+		c.stable.collect = true
+		fields(c)
+		c.stable.collect = false
+
+		count = c.stable.optional
+		size = (count + 7) / 8
+		bits = c.stableBits[c.stable.bits+len(bits):][:size]
+
+		c.stable.index, c.stable.optional, c.stable.fixed = 0, 0, 0
+	}
+	if uint32(size) > dec.length {
+		dec.err = fmt.Errorf("%w: bitvector of %d bytes, message length %d", ErrInvalidActiveFields, size, dec.length)
+		return
+	}
+	DecodeBitvector(dec, bits, uint64(count))
+	if dec.err != nil {
+		dec.err = fmt.Errorf("%w: %v", ErrInvalidActiveFields, dec.err)
+		return
+	}
+	// Collect the present fields and their static sizes, and ensure no fields
+	// are marked active beyond the known ones
+	c.stable.collect = true
+	fields(c)
+	c.stable.collect = false
+
+	if !c.stable.profile {
+		for i := c.stable.index; i < count; i++ {
+			if bits[i/8]&(1<<(i%8)) != 0 {
+				dec.err = fmt.Errorf("%w: unknown field %d active", ErrInvalidActiveFields, i)
+				return
+			}
+		}
+	}
+	length := dec.length - uint32(size)
+	if c.stable.fixed > length || (!c.stable.dynamic && c.stable.fixed != length) {
+		dec.err = fmt.Errorf("%w: present fields need %d bytes, have %d", ErrInvalidActiveFields, c.stable.fixed, length)
+		return
+	}
+	// Descend into a new dynamic type to track a new sub-length and work with a
+	// fresh set of dynamic offsets relative to the end of the bitvector
+	dec.descendIntoDynamic(length)
+	defer dec.ascendFromDynamic()
+
+	dec.startDynamics(c.stable.fixed)
+	defer dec.flushDynamics()
+
+	c.stable.index, c.stable.optional = 0, 0
+	fields(c)
+}
+
+// sizeStable measures a StableContainer or Profile.
+func sizeStable(c *Codec, fields func(c *Codec), size int) {
+	// The layout depends on the present fields, so it must never be cached
+	c.siz.dynamic, c.siz.varying = true, true
+
+	// Profiles only have a bitvector for their optional fields, so count them in
+	// order to know its size
+	if c.stable.profile {
+		c.stable.collect = true
+		fields(c)
+		c.stable.collect = false
+
+		size = (c.stable.optional + 7) / 8
+		c.stable.index, c.stable.optional, c.stable.fixed = 0, 0, 0
+	}
+	c.siz.size += uint32(size)
+	c.siz.static += uint32(size)
+	fields(c)
+}
+
+// hashStable computes the merkle root of a StableContainer or Profile.
+func hashStable(c *Codec, fields func(c *Codec), size int) {
+	h := c.has
+	h.markMerkleized()
+
+	h.descendLayer()
+	fields(c)
+	h.ascendLayer(c.stable.capacity)
+
+	h.descendPackedLayer(256)
+	h.insertBlobChunks(c.stableBits[c.stable.bits:][:size])
+	h.ascendLayer((c.stable.capacity + 255) / 256)
+
+	h.mixinChunks()
+}
+
+// stableField tracks the next field of a StableContainer or Profile, returning
+// whether it is present. Size is the number of bytes the field occupies in the
+// static section of the container if it is known upfront, or zero if it needs
+// to be added by the caller via stableSize.
+//
+// When decoding, present is ignored and is instead retrieved from the bitvector
+// prefix.
+func (c *Codec) stableField(present bool, size uint32, dynamic bool) bool {
+	s := &c.stable
+	if !s.active || s.progressive {
+		panic("ssz: optional field outside of stable container")
+	}
+	if uint64(s.index) >= s.capacity {
+		panic(fmt.Sprintf("ssz: field %d beyond capacity %d", s.index, s.capacity))
+	}
+	required := s.required
+	s.required = false
+
+	var (
+		active  = c.stableBits[s.bits:]
+		optbits = c.stableBits[s.bits+int(s.capacity+7)/8:]
+	)
+	if c.dec != nil {
+		switch {
+		case required:
+			present = true
+		case s.profile:
+			present = optbits[s.optional/8]&(1<<(s.optional%8)) != 0
+		default:
+			present = active[s.index/8]&(1<<(s.index%8)) != 0
+		}
+	} else if required && !present {
+		panic(fmt.Sprintf("ssz: required field %d missing", s.index))
+	}
+	// Track the field in the active and optional bitvectors. For the decoder,
+	// these were already filled from the bitvector prefix.
+	if c.dec == nil && present {
+		active[s.index/8] |= 1 << (s.index % 8)
+		if s.profile && !required {
+			optbits[s.optional/8] |= 1 << (s.optional % 8)
+		}
+	}
+	if s.collect && present {
+		s.fixed += size
+		s.dynamic = s.dynamic || dynamic
+	}
+	if c.has != nil && !present {
+		c.has.insertChunk([32]byte{})
+	}
+	s.index++
+	if s.profile && !required {
+		s.optional++
+	}
+	return present
+}
+
+// stableSize adds the static size of a present field to the container's layout
+// if the fields are being collected, returning whether that was the case (i.e.
+// the field must not be processed further).
+func (c *Codec) stableSize(size uint32) bool {
+	if c.stable.collect {
+		c.stable.fixed += size
+		return true
+	}
+	return false
+}
+
+// DefineOptionalBool defines the next field of a StableContainer or Profile as
+// an optional boolean, a nil pointer denoting absence.
+func DefineOptionalBool[T ~bool](c *Codec, v **T) {
+	if !c.stableField(*v != nil, 1, false) {
+		if c.dec != nil {
+			*v = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *v == nil {
+		*v = new(T)
+	}
+	DefineBool(c, *v)
+}
+
+// DefineOptionalUint8 defines the next field of a StableContainer or Profile as
+// an optional uint8, a nil pointer denoting absence.
+func DefineOptionalUint8[T ~uint8](c *Codec, n **T) {
+	if !c.stableField(*n != nil, 1, false) {
+		if c.dec != nil {
+			*n = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *n == nil {
+		*n = new(T)
+	}
+	DefineUint8(c, *n)
+}
+
+// DefineOptionalUint16 defines the next field of a StableContainer or Profile as
+// an optional uint16, a nil pointer denoting absence.
+func DefineOptionalUint16[T ~uint16](c *Codec, n **T) {
+	if !c.stableField(*n != nil, 2, false) {
+		if c.dec != nil {
+			*n = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *n == nil {
+		*n = new(T)
+	}
+	DefineUint16(c, *n)
+}
+
+// DefineOptionalUint32 defines the next field of a StableContainer or Profile as
+// an optional uint32, a nil pointer denoting absence.
+func DefineOptionalUint32[T ~uint32](c *Codec, n **T) {
+	if !c.stableField(*n != nil, 4, false) {
+		if c.dec != nil {
+			*n = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *n == nil {
+		*n = new(T)
+	}
+	DefineUint32(c, *n)
+}
+
+// DefineOptionalUint64 defines the next field of a StableContainer or Profile as
+// an optional uint64, a nil pointer denoting absence.
+func DefineOptionalUint64[T ~uint64](c *Codec, n **T) {
+	if !c.stableField(*n != nil, 8, false) {
+		if c.dec != nil {
+			*n = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *n == nil {
+		*n = new(T)
+	}
+	DefineUint64(c, *n)
+}
+
+// DefineOptionalStaticBytes defines the next field of a StableContainer or Profile
+// as an optional static binary blob, a nil pointer denoting absence.
+//
+// Note, only byte arrays are supported, as the size of a missing blob needs to be
+// known when decoding.
+func DefineOptionalStaticBytes[T staticBinary](c *Codec, blob **T) {
+	var sizer T
+	if len(sizer) == 0 {
+		panic("ssz: optional static bytes must be byte arrays")
+	}
+	if !c.stableField(*blob != nil, uint32(len(sizer)), false) {
+		if c.dec != nil {
+			*blob = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *blob == nil {
+		*blob = new(T)
+	}
+	DefineStaticBytes(c, binaryBytes(*blob))
+}
+
+// DefineOptionalStaticObject defines the next field of a StableContainer or Profile
+// as an optional static object, a nil pointer denoting absence.
+func DefineOptionalStaticObject[T newableStaticObject[U], U any](c *Codec, obj *T) {
+	if !c.stableField(*obj != nil, 0, false) {
+		if c.dec != nil {
+			*obj = nil
+		}
+		return
+	}
+	if *obj == nil {
+		*obj = T(new(U))
+	}
+	if c.stableSize((*obj).SizeSSZ()) {
+		return
+	}
+	DefineStaticObject(c, obj)
+}
+
+// DefineOptionalDynamicBytesOffset defines the next field of a StableContainer or
+// Profile as an optional dynamic binary blob, a nil slice denoting absence.
+func DefineOptionalDynamicBytesOffset(c *Codec, blob *[]byte) {
+	if !c.stableField(*blob != nil, 4, true) {
+		if c.dec != nil {
+			*blob = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *blob == nil {
+		*blob = []byte{}
+	}
+	DefineDynamicBytesOffset(c, blob)
+}
+
+// DefineOptionalDynamicBytesContent defines the next field of a StableContainer or
+// Profile as an optional dynamic binary blob, a nil slice denoting absence.
+func DefineOptionalDynamicBytesContent(c *Codec, blob *[]byte, maxSize uint32) {
+	if c.stable.collect || *blob == nil {
+		return
+	}
+	DefineDynamicBytesContent(c, blob, maxSize)
+}
+
+// DefineOptionalDynamicObjectOffset defines the next field of a StableContainer or
+// Profile as an optional dynamic object, a nil pointer denoting absence.
+func DefineOptionalDynamicObjectOffset[T newableDynamicObject[U], U any](c *Codec, obj *T) {
+	if !c.stableField(*obj != nil, 4, true) {
+		if c.dec != nil {
+			*obj = nil
+		}
+		return
+	}
+	if c.stableSize(0) {
+		return
+	}
+	if *obj == nil {
+		*obj = T(new(U))
+	}
+	DefineDynamicObjectOffset(c, obj)
+}
+
+// DefineOptionalDynamicObjectContent defines the next field of a StableContainer or
+// Profile as an optional dynamic object, a nil pointer denoting absence.
+func DefineOptionalDynamicObjectContent[T newableDynamicObject[U], U any](c *Codec, obj *T) {
+	if c.stable.collect || *obj == nil {
+		return
+	}
+	DefineDynamicObjectContent(c, obj)
+}
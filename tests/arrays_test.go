@@ -0,0 +1,63 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// testArrays is a container with vectors of uint64s, static and dynamic objects.
+type testArrays struct {
+	Slashings    [5]uint64
+	Checkpoints  [3]*types.Checkpoint
+	Attestations [2]*types.Attestation
+}
+
+func (t *testArrays) SizeSSZ(fixed bool) uint32 {
+	size := uint32(5*8 + 3*40 + 4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeSliceOfDynamicObjects(t.Attestations[:])
+	return size
+}
+func (t *testArrays) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineArrayOfUint64s(codec, t.Slashings[:])                  // Field  (0) - Slashings    -  40 bytes
+	ssz.DefineArrayOfStaticObjects(codec, t.Checkpoints[:])          // Field  (1) - Checkpoints  - 120 bytes
+	ssz.DefineArrayOfDynamicObjectsOffset(codec, t.Attestations[:])  // Offset (2) - Attestations -   4 bytes
+	ssz.DefineArrayOfDynamicObjectsContent(codec, t.Attestations[:]) // Field  (2) - Attestations -   ? bytes
+}
+
+// Tests that vectors of uint64s, static and dynamic objects can be encoded,
+// decoded and hashed.
+func TestArrays(t *testing.T) {
+	obj := &testArrays{
+		Slashings: [5]uint64{1, 2, 3, 4, 5},
+		Checkpoints: [3]*types.Checkpoint{
+			{Epoch: 6, Root: types.Hash{0x07}},
+			{Epoch: 8, Root: types.Hash{0x09}},
+			{Epoch: 10, Root: types.Hash{0x0b}},
+		},
+		Attestations: [2]*types.Attestation{
+			{AggregationBits: []byte{0x0c, 0x01}, Data: &types.AttestationData{Slot: 13, Source: new(types.Checkpoint), Target: new(types.Checkpoint)}},
+			{AggregationBits: []byte{0x0e}, Data: &types.AttestationData{Slot: 15, Source: new(types.Checkpoint), Target: new(types.Checkpoint)}},
+		},
+	}
+	testRoundTrip(t, obj, nil, "a0af824c155a4fc1c6368a53c66b41cb879ecf8f1afa50dbf6f97650401e5f71")
+
+	// Ensure a bad first offset within the dynamic vector is rejected
+	bad, err := ssz.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	bad[5*8+3*40+4]++
+	if err := ssz.DecodeFromBytes(bad, new(testArrays)); !errors.Is(err, ssz.ErrFirstOffsetMismatch) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ssz.ErrFirstOffsetMismatch)
+	}
+}
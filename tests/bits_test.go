@@ -0,0 +1,194 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// testBitvectors is a container with a single-chunk and a multi-chunk bitvector,
+// neither of them aligned to byte boundaries.
+type testBitvectors struct {
+	Short [2]byte
+	Long  [38]byte
+}
+
+func (t *testBitvectors) SizeSSZ() uint32 { return 2 + 38 }
+func (t *testBitvectors) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineBitvector(codec, t.Short[:], 10) // Field (0) - Short -  2 bytes
+	ssz.DefineBitvector(codec, t.Long[:], 300) // Field (1) - Long  - 38 bytes
+}
+
+// Tests that bitvectors can be encoded, decoded and hashed.
+func TestBitvectors(t *testing.T) {
+	obj := &testBitvectors{Short: [2]byte{0x05, 0x02}}
+	for i := 0; i < 37; i++ {
+		obj.Long[i] = 0xff
+	}
+	obj.Long[37] = 0x0f
+
+	testRoundTrip(t, obj, nil, "369b2e8ce868c31362db5e49d8d93b9d29719364bad6888e679a02587f9ae15e")
+}
+
+// Tests that bitvectors with non-zero padding bits are rejected, both when
+// encoding and when decoding.
+func TestBitvectorsInvalid(t *testing.T) {
+	tests := []struct {
+		index int  // Offset of the tampered byte in the encoding
+		value byte // Value to set the byte to, with junk padding bits
+	}{
+		{1, 0x04},      // Short: 10 bits
+		{2 + 37, 0x10}, // Long: 300 bits
+	}
+	for i, tt := range tests {
+		blob := make([]byte, 2+38)
+		blob[tt.index] = tt.value
+
+		obj := new(testBitvectors)
+		copy(obj.Short[:], blob[:2])
+		copy(obj.Long[:], blob[2:])
+
+		if err := ssz.EncodeToBytes(make([]byte, len(blob)), obj); !errors.Is(err, ssz.ErrJunkInBitvector) {
+			t.Errorf("test %d: buffer encoding error mismatch: have %v, want %v", i, err, ssz.ErrJunkInBitvector)
+		}
+		if err := ssz.EncodeToStream(new(bytes.Buffer), obj); !errors.Is(err, ssz.ErrJunkInBitvector) {
+			t.Errorf("test %d: stream encoding error mismatch: have %v, want %v", i, err, ssz.ErrJunkInBitvector)
+		}
+		if err := ssz.DecodeFromStream(bytes.NewReader(blob), new(testBitvectors), uint32(len(blob))); !errors.Is(err, ssz.ErrJunkInBitvector) {
+			t.Errorf("test %d: stream decoding error mismatch: have %v, want %v", i, err, ssz.ErrJunkInBitvector)
+		}
+		if err := ssz.DecodeFromBytes(blob, new(testBitvectors)); !errors.Is(err, ssz.ErrJunkInBitvector) {
+			t.Errorf("test %d: buffer decoding error mismatch: have %v, want %v", i, err, ssz.ErrJunkInBitvector)
+		}
+	}
+}
+
+// testMissizedBitvector is a container with a bitvector backed by a byte slice
+// of the wrong length.
+type testMissizedBitvector struct {
+	Bits []byte
+}
+
+func (t *testMissizedBitvector) SizeSSZ() uint32 { return 2 }
+func (t *testMissizedBitvector) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineBitvector(codec, t.Bits, 10) // Field (0) - Bits - 2 bytes
+}
+
+// Tests that bitvectors backed by the wrong number of bytes are rejected instead
+// of silently producing a different encoding.
+func TestBitvectorsMissized(t *testing.T) {
+	for _, bits := range [][]byte{nil, {0x01}, {0x01, 0x00, 0x00}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("bitvector of %d bytes accepted", len(bits))
+				}
+			}()
+			ssz.DecodeFromBytes(make([]byte, 2), &testMissizedBitvector{Bits: bits})
+		}()
+	}
+}
+
+// testBitlist is a container with a single bitlist.
+type testBitlist struct {
+	Bits []byte
+}
+
+func (t *testBitlist) SizeSSZ(fixed bool) uint32 {
+	size := uint32(4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeBitlist(t.Bits)
+	return size
+}
+func (t *testBitlist) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineBitlistOffset(codec, &t.Bits) // Offset (0) - Bits - 4 bytes
+
+	ssz.DefineBitlistContent(codec, &t.Bits, 300) // Offset (0) - Bits - 4 bytes
+}
+
+// Tests that bitlists can be encoded, decoded and hashed, with the delimiter bit
+// being excluded from the hashed data.
+func TestBitlists(t *testing.T) {
+	tests := []struct {
+		bits []byte
+		root string
+	}{
+		{[]byte{0x01}, "7a0501f5957bdf9cb3a8ff4966f02265f968658b7a9c62642cba1165e86642f5"},
+		{append(bytes.Repeat([]byte{0xff}, 32), 0x01), "b3327406854ffab96af59832dfa3f690f72c4f898e2ffd4ef3e90cc2fb876b43"},
+		{[]byte{0x0f, 0x03}, "bda4a8914ee97d27d612898624ff76b5121117fdfc95f28e62ad4da4c6e15f83"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			testRoundTrip(t, &testBitlist{Bits: tt.bits}, nil, tt.root)
+		})
+	}
+}
+
+// Tests that bitlists with missing delimiters or too many bits are rejected.
+func TestBitlistsInvalid(t *testing.T) {
+	tests := []struct {
+		blob []byte
+		err  error
+	}{
+		{[]byte{0x04, 0x00, 0x00, 0x00}, ssz.ErrJunkInBitlist},
+		{[]byte{0x04, 0x00, 0x00, 0x00, 0x05, 0x00}, ssz.ErrJunkInBitlist},
+		{append([]byte{0x04, 0x00, 0x00, 0x00}, append(make([]byte, 37), 0x20)...), ssz.ErrMaxItemsExceeded},
+		{append([]byte{0x04, 0x00, 0x00, 0x00}, append(make([]byte, 38), 0x01)...), ssz.ErrMaxItemsExceeded},
+	}
+	for i, tt := range tests {
+		if err := ssz.DecodeFromStream(bytes.NewReader(tt.blob), new(testBitlist), uint32(len(tt.blob))); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: stream error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err := ssz.DecodeFromBytes(tt.blob, new(testBitlist)); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: buffer error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// Bitlists missing their delimiters must not be encoded either
+	obj := &testBitlist{Bits: []byte{0x05, 0x00}}
+	if err := ssz.EncodeToStream(new(bytes.Buffer), obj); !errors.Is(err, ssz.ErrJunkInBitlist) {
+		t.Errorf("stream encoding error mismatch: have %v, want %v", err, ssz.ErrJunkInBitlist)
+	}
+	if err := ssz.EncodeToBytes(make([]byte, ssz.Size(obj)), obj); !errors.Is(err, ssz.ErrJunkInBitlist) {
+		t.Errorf("buffer encoding error mismatch: have %v, want %v", err, ssz.ErrJunkInBitlist)
+	}
+}
+
+// Tests that nil bitlists are treated as empty ones, so zero value objects can
+// be round tripped.
+func TestBitlistsNil(t *testing.T) {
+	// Nested objects are not optional, only the bitlist is left at its zero value
+	obj := &types.Attestation{
+		Data: &types.AttestationData{Source: new(types.Checkpoint), Target: new(types.Checkpoint)},
+	}
+	blob, err := ssz.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	dec := new(types.Attestation)
+	if err := ssz.DecodeFromBytes(blob, dec); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if !bytes.Equal(dec.AggregationBits, []byte{0x01}) {
+		t.Errorf("decoded bitlist mismatch: have %x, want 01", dec.AggregationBits)
+	}
+	reblob, err := ssz.Marshal(dec)
+	if err != nil {
+		t.Fatalf("failed to re-encode: %v", err)
+	}
+	if !bytes.Equal(reblob, blob) {
+		t.Errorf("re-encoding mismatch: have %x, want %x", reblob, blob)
+	}
+	if have, want := ssz.HashSequential(obj), ssz.HashSequential(dec); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+}
@@ -0,0 +1,63 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/karalabe/ssz"
+)
+
+// testBools is a container with all the different boolean field types.
+type testBools struct {
+	Flag  bool
+	Flags [4]bool
+	List  []bool `ssz-max:"300"`
+}
+
+func (t *testBools) SizeSSZ(fixed bool) uint32 {
+	size := uint32(1 + 4 + 4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeSliceOfBools(t.List)
+	return size
+}
+func (t *testBools) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineBool(codec, &t.Flag)               // Field (0) - Flag  - 1 byte
+	ssz.DefineArrayOfBools(codec, t.Flags[:])    // Field (1) - Flags - 4 bytes
+	ssz.DefineSliceOfBoolsOffset(codec, &t.List) // Offset (2) - List - 4 bytes
+
+	ssz.DefineSliceOfBoolsContent(codec, &t.List, 300) // Field (2) - List - ? bytes
+}
+
+// Tests that booleans and lists/vectors of them can be encoded, decoded and
+// hashed.
+func TestBools(t *testing.T) {
+	obj := &testBools{Flag: true, Flags: [4]bool{true, false, true, true}, List: []bool{false, true, true}}
+	want := []byte{0x01, 0x01, 0x00, 0x01, 0x01, 0x09, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01}
+
+	testRoundTrip(t, obj, want, "2c62869e702584f577cbf3e15cd50c8a10228192f01ad19c5f546ec36353f442")
+}
+
+// Tests that booleans are strictly decoded, rejecting anything besides 0x00 and
+// 0x01, both in the streaming and buffered decoders.
+func TestBoolsInvalid(t *testing.T) {
+	tests := [][]byte{
+		{0x02, 0x01, 0x00, 0x01, 0x01, 0x09, 0x00, 0x00, 0x00},
+		{0x01, 0x01, 0x00, 0xff, 0x01, 0x09, 0x00, 0x00, 0x00},
+		{0x01, 0x01, 0x00, 0x01, 0x01, 0x09, 0x00, 0x00, 0x00, 0x00, 0x80},
+	}
+	for i, blob := range tests {
+		if err := ssz.DecodeFromStream(bytes.NewReader(blob), new(testBools), uint32(len(blob))); !errors.Is(err, ssz.ErrInvalidBoolean) {
+			t.Errorf("test %d: stream error mismatch: have %v, want %v", i, err, ssz.ErrInvalidBoolean)
+		}
+		if err := ssz.DecodeFromBytes(blob, new(testBools)); !errors.Is(err, ssz.ErrInvalidBoolean) {
+			t.Errorf("test %d: buffer error mismatch: have %v, want %v", i, err, ssz.ErrInvalidBoolean)
+		}
+	}
+}
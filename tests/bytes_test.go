@@ -0,0 +1,157 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karalabe/ssz"
+)
+
+// Version is a named fixed-size binary blob.
+type Version [4]byte
+
+// testBinaries is a container with vectors and lists of binary blobs of sizes
+// other than a hash.
+type testBinaries struct {
+	Versions   [2]Version
+	Signatures [][96]byte  `ssz-max:"4"`
+	Blooms     [][256]byte `ssz-max:"2"`
+}
+
+func (t *testBinaries) SizeSSZ(fixed bool) uint32 {
+	size := uint32(8 + 4 + 4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeSliceOfStaticBytes(t.Signatures)
+	size += ssz.SizeSliceOfStaticBytes(t.Blooms)
+	return size
+}
+func (t *testBinaries) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineArrayOfStaticBytes(codec, t.Versions[:])       // Field  (0) - Versions   - 8 bytes
+	ssz.DefineSliceOfStaticBytesOffset(codec, &t.Signatures) // Offset (1) - Signatures - 4 bytes
+	ssz.DefineSliceOfStaticBytesOffset(codec, &t.Blooms)     // Offset (2) - Blooms     - 4 bytes
+
+	ssz.DefineSliceOfStaticBytesContent(codec, &t.Signatures, 4) // Field  (1) - Signatures - ? bytes
+	ssz.DefineSliceOfStaticBytesContent(codec, &t.Blooms, 2)     // Field  (2) - Blooms     - ? bytes
+}
+
+// Tests that vectors and lists of arbitrarily sized binary blobs can be encoded,
+// decoded and hashed.
+func TestArbitraryBinaries(t *testing.T) {
+	obj := &testBinaries{
+		Versions:   [2]Version{{0x01, 0x02, 0x03, 0x04}, {0x05, 0x06, 0x07, 0x08}},
+		Signatures: [][96]byte{[96]byte(bytes.Repeat([]byte{0x09}, 96))},
+		Blooms:     [][256]byte{[256]byte(bytes.Repeat([]byte{0x0a}, 256)), [256]byte(bytes.Repeat([]byte{0x0b}, 256))},
+	}
+	testRoundTrip(t, obj, nil, "54616f9e4e6b02a6e7a68262502057985e27d4e820ec4751e5f2dafa37d0906a")
+}
+
+// testStridedBinaries is a testBinaries with the blobs flattened into byte slices.
+type testStridedBinaries struct {
+	Versions   [8]byte
+	Signatures []byte
+	Blooms     []byte
+}
+
+func (t *testStridedBinaries) SizeSSZ(fixed bool) uint32 {
+	size := uint32(8 + 4 + 4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeSliceOfStridedBytes(t.Signatures)
+	size += ssz.SizeSliceOfStridedBytes(t.Blooms)
+	return size
+}
+func (t *testStridedBinaries) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineArrayOfStridedBytes(codec, t.Versions[:], 4)        // Field  (0) - Versions   - 8 bytes
+	ssz.DefineSliceOfStridedBytesOffset(codec, &t.Signatures, 96) // Offset (1) - Signatures - 4 bytes
+	ssz.DefineSliceOfStridedBytesOffset(codec, &t.Blooms, 256)    // Offset (2) - Blooms     - 4 bytes
+
+	ssz.DefineSliceOfStridedBytesContent(codec, &t.Signatures, 96, 4) // Field  (1) - Signatures - ? bytes
+	ssz.DefineSliceOfStridedBytesContent(codec, &t.Blooms, 256, 2)    // Field  (2) - Blooms     - ? bytes
+}
+
+// Tests that flattened binary blobs are encoded, decoded and hashed the same way
+// as the arrays they were flattened from.
+func TestStridedBinaries(t *testing.T) {
+	obj := &testStridedBinaries{
+		Versions:   [8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		Signatures: bytes.Repeat([]byte{0x09}, 96),
+		Blooms:     append(bytes.Repeat([]byte{0x0a}, 256), bytes.Repeat([]byte{0x0b}, 256)...),
+	}
+	want := &testBinaries{
+		Versions:   [2]Version{{0x01, 0x02, 0x03, 0x04}, {0x05, 0x06, 0x07, 0x08}},
+		Signatures: [][96]byte{[96]byte(bytes.Repeat([]byte{0x09}, 96))},
+		Blooms:     [][256]byte{[256]byte(bytes.Repeat([]byte{0x0a}, 256)), [256]byte(bytes.Repeat([]byte{0x0b}, 256))},
+	}
+	wantBlob, err := ssz.Marshal(want)
+	if err != nil {
+		t.Fatalf("failed to encode arrays: %v", err)
+	}
+	testRoundTrip(t, obj, wantBlob, fmt.Sprintf("%x", ssz.HashSequential(want)))
+
+	// Lists not divisible by their stride must be rejected
+	wantBlob[12]++ // Move a byte from the blooms to the signatures
+	if err := ssz.DecodeFromBytes(wantBlob, new(testStridedBinaries)); !errors.Is(err, ssz.ErrDynamicStaticsIndivisible) {
+		t.Fatalf("indivisible list: unexpected error: %v", err)
+	}
+}
+
+// testZeroStride is a container with a list of flattened blobs of zero stride.
+type testZeroStride struct {
+	Blobs []byte
+}
+
+func (t *testZeroStride) SizeSSZ(fixed bool) uint32 {
+	size := uint32(4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeSliceOfStridedBytes(t.Blobs)
+	return size
+}
+func (t *testZeroStride) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineSliceOfStridedBytesOffset(codec, &t.Blobs, 0) // Offset (0) - Blobs - 4 bytes
+
+	ssz.DefineSliceOfStridedBytesContent(codec, &t.Blobs, 0, 4) // Field  (0) - Blobs - ? bytes
+}
+
+// Tests that invalid strides are rejected by the strided methods themselves, not
+// only by the codec wrappers.
+func TestStridedBinariesInvalid(t *testing.T) {
+	tests := map[string]func(){
+		"decode list": func() {
+			ssz.DecodeFromBytes([]byte{0x04, 0x00, 0x00, 0x00, 0x01}, new(testZeroStride))
+		},
+		"encode array": func() {
+			ssz.EncodeArrayOfStridedBytes(nil, make([]byte, 10), 4)
+		},
+		"decode content": func() {
+			ssz.DecodeSliceOfStridedBytesContent(nil, new([]byte), 0, 4)
+		},
+		"hash array": func() {
+			ssz.HashArrayOfStridedBytes(nil, make([]byte, 10), 4)
+		},
+		"hash content": func() {
+			ssz.HashSliceOfStridedBytesContent(nil, make([]byte, 8), 0, 4)
+		},
+	}
+	for name, fn := range tests {
+		func() {
+			defer func() {
+				if r := recover(); !strings.Contains(fmt.Sprint(r), "invalid strided bytes") {
+					t.Errorf("%s: invalid stride not rejected: %v", name, r)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -27,6 +27,9 @@ var (
 	// untested stuff can fail noisily.
 	consensusSpecTestsDone = make(map[string]map[string]struct{})
 	consensusSpecTestsLock sync.Mutex
+
+	// consensusSpecTestsSuites is the list of test suites to run for each type.
+	consensusSpecTestsSuites = []string{"ssz_zero", "ssz_one", "ssz_nil", "ssz_max", "ssz_lengthy", "ssz_random"}
 )
 
 // TestConsensusSpecs iterates over all the (supported) consensus SSZ types and
@@ -42,13 +45,15 @@ func TestConsensusSpecs(t *testing.T) {
 	testConsensusSpecType[*types.Deposit](t, "Deposit")
 	testConsensusSpecType[*types.DepositData](t, "DepositData")
 	testConsensusSpecType[*types.Eth1Data](t, "Eth1Data")
-	testConsensusSpecType[*types.ExecutionPayload](t, "bellatrix", "ExecutionPayload")
-	testConsensusSpecType[*types.ExecutionPayloadCapella](t, "capella", "ExecutionPayload")
+	testConsensusSpecType[*types.ExecutionPayload](t, "ExecutionPayload", "bellatrix")
+	testConsensusSpecType[*types.ExecutionPayloadCapella](t, "ExecutionPayload", "capella")
 	testConsensusSpecType[*types.HistoricalBatch](t, "HistoricalBatch")
 	testConsensusSpecType[*types.IndexedAttestation](t, "IndexedAttestation")
 	testConsensusSpecType[*types.ProposerSlashing](t, "ProposerSlashing")
 	testConsensusSpecType[*types.SignedBeaconBlockHeader](t, "SignedBeaconBlockHeader")
 	testConsensusSpecType[*types.SignedVoluntaryExit](t, "SignedVoluntaryExit")
+	testConsensusSpecType[*types.SyncAggregate](t, "SyncAggregate")
+	testConsensusSpecType[*types.Validator](t, "Validator")
 	testConsensusSpecType[*types.VoluntaryExit](t, "VoluntaryExit")
 	testConsensusSpecType[*types.Withdrawal](t, "Withdrawal")
 
@@ -91,7 +96,7 @@ func testConsensusSpecType[T newableObject[U], U any](t *testing.T, kind string,
 			return
 		}
 		for _, fork := range forks {
-			if _, err := os.Stat(filepath.Join(consensusSpecTestsRoot, fork.Name(), "ssz_static", kind, "ssz_random")); err == nil {
+			if _, err := os.Stat(filepath.Join(consensusSpecTestsRoot, fork.Name(), "ssz_static", kind)); err == nil {
 				testConsensusSpecType[T, U](t, kind, fork.Name())
 			}
 		}
@@ -99,13 +104,6 @@ func testConsensusSpecType[T newableObject[U], U any](t *testing.T, kind string,
 	}
 	// Some specific fork was requested, look that up explicitly
 	for _, fork := range forks {
-		path := filepath.Join(consensusSpecTestsRoot, fork, "ssz_static", kind, "ssz_random")
-
-		tests, err := os.ReadDir(path)
-		if err != nil {
-			t.Errorf("failed to walk test collection %v: %v", path, err)
-			return
-		}
 		// Track this test suite done, whether succeeds of fails is irrelevant
 		consensusSpecTestsLock.Lock()
 		if _, ok := consensusSpecTestsDone[fork]; !ok {
@@ -114,64 +112,96 @@ func testConsensusSpecType[T newableObject[U], U any](t *testing.T, kind string,
 		consensusSpecTestsDone[fork][kind] = struct{}{}
 		consensusSpecTestsLock.Unlock()
 
-		// Run all the subtests found in the folder
-		for _, test := range tests {
-			t.Run(fmt.Sprintf("%s/%s/%s", fork, kind, test.Name()), func(t *testing.T) {
-				// Parse the input SSZ data and the expected root for the test
-				inSnappy, err := os.ReadFile(filepath.Join(path, test.Name(), "serialized.ssz_snappy"))
-				if err != nil {
-					t.Fatalf("failed to load snapy ssz binary: %v", err)
-				}
-				inSSZ, err := snappy.Decode(nil, inSnappy)
-				if err != nil {
-					t.Fatalf("failed to parse snappy ssz binary: %v", err)
-				}
-				inYAML, err := os.ReadFile(filepath.Join(path, test.Name(), "roots.yaml"))
-				if err != nil {
-					t.Fatalf("failed to load yaml root: %v", err)
-				}
-				inRoot := struct {
-					Root string `yaml:"root"`
-				}{}
-				if err = yaml.Unmarshal(inYAML, &inRoot); err != nil {
-					t.Fatalf("failed to parse yaml root: %v", err)
-				}
-				// Do a decode/encode round. Would be nicer to parse out the value
-				// from yaml and check that too, but hex-in-yaml makes everything
-				// beyond annoying. C'est la vie.
-				obj := T(new(U))
-				if err := ssz.DecodeFromStream(bytes.NewReader(inSSZ), obj, uint32(len(inSSZ))); err != nil {
-					t.Fatalf("failed to decode SSZ stream: %v", err)
-				}
-				blob := new(bytes.Buffer)
-				if err := ssz.EncodeToStream(blob, obj); err != nil {
-					t.Fatalf("failed to re-encode SSZ stream: %v", err)
-				}
-				if !bytes.Equal(blob.Bytes(), inSSZ) {
-					t.Fatalf("re-encoded stream mismatch: have %x, want %x", blob, inSSZ)
-				}
-				obj = T(new(U))
-				if err := ssz.DecodeFromBytes(inSSZ, obj); err != nil {
-					t.Fatalf("failed to decode SSZ buffer: %v", err)
-				}
-				bin := make([]byte, ssz.Size(obj))
-				if err := ssz.EncodeToBytes(bin, obj); err != nil {
-					t.Fatalf("failed to re-encode SSZ buffer: %v", err)
-				}
-				if !bytes.Equal(bin, inSSZ) {
-					t.Fatalf("re-encoded bytes mismatch: have %x, want %x", bin, inSSZ)
-				}
-				// Encoder/decoder seems to work, check if the size reported by the
-				// encoded object actually matches the encoded stream
-				if size := ssz.Size(obj); size != uint32(len(inSSZ)) {
-					t.Fatalf("reported/generated size mismatch: reported %v, generated %v", size, len(inSSZ))
+		// Run all the test suites available for the type. Not all of them exist
+		// for every preset and type (e.g. lengthy only for dynamic types), so only
+		// fail if none of them could be found.
+		var found bool
+		for _, suite := range consensusSpecTestsSuites {
+			path := filepath.Join(consensusSpecTestsRoot, fork, "ssz_static", kind, suite)
+
+			tests, err := os.ReadDir(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
 				}
-				// TODO(karalabe): check the root hash of the object
-			})
+				t.Errorf("failed to walk test collection %v: %v", path, err)
+				return
+			}
+			found = true
+
+			// Run all the subtests found in the folder
+			for _, test := range tests {
+				t.Run(fmt.Sprintf("%s/%s/%s/%s", fork, kind, suite, test.Name()), func(t *testing.T) {
+					testConsensusSpecCase[T, U](t, filepath.Join(path, test.Name()))
+				})
+			}
+		}
+		if !found {
+			t.Errorf("no test suites found for %v/%v", fork, kind)
 		}
 	}
 }
 
+// testConsensusSpecCase runs the encoding/decoding/hashing round of a single
+// consensus spec test case, located in the given folder.
+func testConsensusSpecCase[T newableObject[U], U any](t *testing.T, path string) {
+	// Parse the input SSZ data and the expected root for the test
+	inSnappy, err := os.ReadFile(filepath.Join(path, "serialized.ssz_snappy"))
+	if err != nil {
+		t.Fatalf("failed to load snapy ssz binary: %v", err)
+	}
+	inSSZ, err := snappy.Decode(nil, inSnappy)
+	if err != nil {
+		t.Fatalf("failed to parse snappy ssz binary: %v", err)
+	}
+	inYAML, err := os.ReadFile(filepath.Join(path, "roots.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load yaml root: %v", err)
+	}
+	inRoot := struct {
+		Root string `yaml:"root"`
+	}{}
+	if err = yaml.Unmarshal(inYAML, &inRoot); err != nil {
+		t.Fatalf("failed to parse yaml root: %v", err)
+	}
+	// Do a decode/encode round. Would be nicer to parse out the value
+	// from yaml and check that too, but hex-in-yaml makes everything
+	// beyond annoying. C'est la vie.
+	obj := T(new(U))
+	if err := ssz.DecodeFromStream(bytes.NewReader(inSSZ), obj, uint32(len(inSSZ))); err != nil {
+		t.Fatalf("failed to decode SSZ stream: %v", err)
+	}
+	blob := new(bytes.Buffer)
+	if err := ssz.EncodeToStream(blob, obj); err != nil {
+		t.Fatalf("failed to re-encode SSZ stream: %v", err)
+	}
+	if !bytes.Equal(blob.Bytes(), inSSZ) {
+		t.Fatalf("re-encoded stream mismatch: have %x, want %x", blob, inSSZ)
+	}
+	if hash := fmt.Sprintf("%#x", ssz.HashSequential(obj)); hash != inRoot.Root {
+		t.Fatalf("stream decoded root mismatch: have %s, want %s", hash, inRoot.Root)
+	}
+	obj = T(new(U))
+	if err := ssz.DecodeFromBytes(inSSZ, obj); err != nil {
+		t.Fatalf("failed to decode SSZ buffer: %v", err)
+	}
+	bin := make([]byte, ssz.Size(obj))
+	if err := ssz.EncodeToBytes(bin, obj); err != nil {
+		t.Fatalf("failed to re-encode SSZ buffer: %v", err)
+	}
+	if !bytes.Equal(bin, inSSZ) {
+		t.Fatalf("re-encoded bytes mismatch: have %x, want %x", bin, inSSZ)
+	}
+	if hash := fmt.Sprintf("%#x", ssz.HashSequential(obj)); hash != inRoot.Root {
+		t.Fatalf("buffer decoded root mismatch: have %s, want %s", hash, inRoot.Root)
+	}
+	// Encoder/decoder seems to work, check if the size reported by the
+	// encoded object actually matches the encoded stream
+	if size := ssz.Size(obj); size != uint32(len(inSSZ)) {
+		t.Fatalf("reported/generated size mismatch: reported %v, generated %v", size, len(inSSZ))
+	}
+}
+
 // TestConsensusSpecs iterates over all the (supported) consensus SSZ types and
 // runs the encoding/decoding/hashing benchmark round.
 func BenchmarkConsensusSpecs(b *testing.B) {
@@ -191,6 +221,8 @@ func BenchmarkConsensusSpecs(b *testing.B) {
 	benchmarkConsensusSpecType[*types.ProposerSlashing](b, "deneb", "ProposerSlashing")
 	benchmarkConsensusSpecType[*types.SignedBeaconBlockHeader](b, "deneb", "SignedBeaconBlockHeader")
 	benchmarkConsensusSpecType[*types.SignedVoluntaryExit](b, "deneb", "SignedVoluntaryExit")
+	benchmarkConsensusSpecType[*types.SyncAggregate](b, "deneb", "SyncAggregate")
+	benchmarkConsensusSpecType[*types.Validator](b, "deneb", "Validator")
 	benchmarkConsensusSpecType[*types.VoluntaryExit](b, "deneb", "VoluntaryExit")
 	benchmarkConsensusSpecType[*types.Withdrawal](b, "deneb", "Withdrawal")
 }
@@ -264,4 +296,13 @@ func benchmarkConsensusSpecType[T newableObject[U], U any](b *testing.B, fork, k
 			}
 		}
 	})
+	b.Run(fmt.Sprintf("%s/hash-sequential", kind), func(b *testing.B) {
+		b.SetBytes(int64(len(inSSZ)))
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			ssz.HashSequential(inObj)
+		}
+	})
 }
@@ -0,0 +1,242 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// Tests that decoding failures report the path of the failing field and the
+// position within the input, while still unwrapping to the underlying error.
+func TestDecodeError(t *testing.T) {
+	// Create a block with an attestation deep inside exceeding its bitlist limit
+	block := new(types.BeaconBlock)
+	fillRandom(rand.New(rand.NewSource(1)), reflect.ValueOf(block).Elem(), "")
+
+	block.Body.Attestations = make([]*types.Attestation, 20)
+	for i := range block.Body.Attestations {
+		block.Body.Attestations[i] = &types.Attestation{
+			AggregationBits: []byte{0x01},
+			Data:            &types.AttestationData{Source: new(types.Checkpoint), Target: new(types.Checkpoint)},
+		}
+	}
+	oversized := bytes.Repeat([]byte{0xaa}, 258)
+	oversized[len(oversized)-1] = 0x01
+	block.Body.Attestations[17].AggregationBits = oversized
+
+	blob := make([]byte, ssz.Size(block))
+	if err := ssz.EncodeToBytes(blob, block); err != nil {
+		t.Fatalf("failed to encode block: %v", err)
+	}
+	start := bytes.Index(blob, oversized)
+
+	errBytes := ssz.DecodeFromBytes(blob, new(types.BeaconBlock))
+	errStream := ssz.DecodeFromStream(bytes.NewReader(blob), new(types.BeaconBlock), uint32(len(blob)))
+	for _, err := range []error{errBytes, errStream} {
+		testDecodeError(t, err, ssz.ErrMaxItemsExceeded, "body.attestations[17].aggregation_bits", start)
+	}
+	// Check failures in static fields and at the top level
+	withdrawal := make([]byte, ssz.Size(new(types.Withdrawal)))
+
+	err := ssz.DecodeFromBytes(withdrawal[:20], new(types.Withdrawal))
+	testDecodeError(t, err, ssz.ErrShortBuffer, "address", 16)
+
+	err = ssz.DecodeFromBytes(append(withdrawal, 0x00), new(types.Withdrawal))
+	testDecodeError(t, err, ssz.ErrTrailingBytes, "", len(withdrawal))
+
+	// Check that unnamed fields are left out of the path
+	err = ssz.DecodeFromBytes(make([]byte, 4), new(testUints))
+	testDecodeError(t, err, ssz.ErrShortBuffer, "", 3)
+
+	// Check that unnamed fields are not blamed on preceding named ones
+	tests := []struct {
+		index int
+		value byte
+		err   error
+		path  string
+	}{
+		{8, 0x10, ssz.ErrJunkInBitvector, ""},
+		{49, 0x02, ssz.ErrInvalidBoolean, ""},
+		{50, 0x02, ssz.ErrInvalidBoolean, "b"},
+	}
+	for _, tt := range tests {
+		blob := make([]byte, new(testMixedNames).SizeSSZ())
+		blob[tt.index] = tt.value
+
+		err = ssz.DecodeFromBytes(blob, new(testMixedNames))
+		testDecodeError(t, err, tt.err, tt.path, tt.index+1)
+	}
+	// Check that absent optional fields do not lend their names to present ones
+	for _, tt := range []struct {
+		blob []byte
+		path string
+	}{
+		{[]byte{0x02, 0x02}, ""},
+		{[]byte{0x03, 0, 0, 0, 0, 0, 0, 0, 0, 0x02}, ""},
+		{[]byte{0x06, 0x00, 0x02}, "b"},
+	} {
+		err = ssz.DecodeFromBytes(tt.blob, new(testMixedNamesStable))
+		testDecodeError(t, err, ssz.ErrInvalidBoolean, tt.path, len(tt.blob))
+	}
+}
+
+// testMixedNames is a container interleaving named and unnamed fields.
+type testMixedNames struct {
+	A          uint64
+	Bits       [1]byte
+	Checkpoint *types.Checkpoint
+	Flag       bool
+	B          bool
+}
+
+func (t *testMixedNames) SizeSSZ() uint32 { return 51 }
+func (t *testMixedNames) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("a"), &t.A)
+	ssz.DefineBitvector(codec, t.Bits[:], 4)
+	ssz.DefineStaticObject(codec.Named("checkpoint"), &t.Checkpoint)
+	ssz.DefineBool(codec, &t.Flag)
+	ssz.DefineBool(codec.Named("b"), &t.B)
+}
+
+// testMixedNamesStable is a StableContainer interleaving named and unnamed fields.
+type testMixedNamesStable struct {
+	A    *uint64
+	Flag *bool
+	B    *bool
+}
+
+func (t *testMixedNamesStable) SizeSSZ(fixed bool) uint32 {
+	size := uint32(1)
+	if t.A != nil {
+		size += 8
+	}
+	if t.Flag != nil {
+		size += 1
+	}
+	if t.B != nil {
+		size += 1
+	}
+	return size
+}
+func (t *testMixedNamesStable) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStableContainer(codec, 4, func(codec *ssz.Codec) {
+		ssz.DefineOptionalUint64(codec.Named("a"), &t.A)
+		ssz.DefineOptionalBool(codec, &t.Flag)
+		ssz.DefineOptionalBool(codec.Named("b"), &t.B)
+	})
+}
+
+func testDecodeError(t *testing.T, err error, want error, path string, offset int) {
+	t.Helper()
+
+	var derr *ssz.DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("error type mismatch: have %T, want %T", err, derr)
+	}
+	if !errors.Is(err, want) {
+		t.Errorf("underlying error mismatch: have %v, want %v", derr.Err, want)
+	}
+	if derr.Path != path {
+		t.Errorf("path mismatch: have %q, want %q", derr.Path, path)
+	}
+	if derr.Offset != offset {
+		t.Errorf("offset mismatch: have %d, want %d", derr.Offset, offset)
+	}
+}
+
+// Tests that encoding into byte buffers too short for the object, or objects that
+// misreport their sizes, fail with errors instead of panicking.
+func TestEncodeError(t *testing.T) {
+	obj := new(types.Withdrawal)
+	fillRandom(rand.New(rand.NewSource(1)), reflect.ValueOf(obj).Elem(), "")
+	size := int(ssz.Size(obj))
+
+	// Short buffers must be rejected before anything is written
+	buf := make([]byte, size-1)
+	err := ssz.EncodeToBytes(buf, obj)
+
+	var short *ssz.ShortWriteError
+	if !errors.As(err, &short) || !errors.Is(err, ssz.ErrShortBuffer) {
+		t.Fatalf("short buffer: unexpected error: %v", err)
+	}
+	if short.Needed != size || short.Available != size-1 || short.Written != 0 {
+		t.Errorf("short buffer: invalid counts: %v", err)
+	}
+	if !bytes.Equal(buf, make([]byte, size-1)) {
+		t.Errorf("short buffer: written into: %x", buf)
+	}
+	// Oversized buffers must only have the object's bytes overwritten
+	buf = bytes.Repeat([]byte{0xff}, size+4)
+	if err := ssz.EncodeToBytes(buf, obj); err != nil {
+		t.Fatalf("oversized buffer: failed to encode: %v", err)
+	}
+	if !bytes.Equal(buf[size:], []byte{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("oversized buffer: tail overwritten: %x", buf[size:])
+	}
+	// Objects declaring less than they encode must not write past their declared
+	// size, even if the buffer has room for it
+	buf = bytes.Repeat([]byte{0xff}, 44)
+	err = ssz.EncodeToBytes(buf, new(testBrokenStatic))
+	if !errors.As(err, &short) {
+		t.Fatalf("undersized object: unexpected error: %v", err)
+	}
+	if short.Needed != 40 || short.Available != 36 || short.Written != 8 {
+		t.Errorf("undersized object: invalid counts: %v", err)
+	}
+	if !bytes.Equal(buf[36:], bytes.Repeat([]byte{0xff}, 8)) {
+		t.Errorf("undersized object: tail overwritten: %x", buf[36:])
+	}
+	// Objects declaring more than they encode must be reported too
+	err = ssz.EncodeToBytes(make([]byte, 16), &testBrokenDynamic{Data: []byte{1}})
+	if !errors.Is(err, ssz.ErrSizeMismatch) {
+		t.Errorf("oversized object: unexpected error: %v", err)
+	}
+	// Same for the reflection based encoder, which sizes the object itself
+	buf = bytes.Repeat([]byte{0xff}, 44)
+	if err := ssz.EncodeReflect(buf, new(testBrokenStatic)); err != nil {
+		t.Fatalf("reflect: failed to encode: %v", err)
+	}
+	if !bytes.Equal(buf[40:], bytes.Repeat([]byte{0xff}, 4)) {
+		t.Errorf("reflect: tail overwritten: %x", buf[40:])
+	}
+}
+
+// testUnhashable is an asymmetric object that only defines its encoder and its
+// decoder, but not its hasher.
+type testUnhashable struct {
+	Value uint64
+}
+
+func (t *testUnhashable) SizeSSZ() uint32 { return 8 }
+func (t *testUnhashable) DefineSSZ(codec *ssz.Codec) {
+	codec.DefineEncoder(func(enc *ssz.Encoder) {
+		ssz.EncodeUint64(enc, t.Value) // Field (0) - Value - 8 bytes
+	})
+	codec.DefineDecoder(func(dec *ssz.Decoder) {
+		ssz.DecodeUint64(dec, &t.Value) // Field (0) - Value - 8 bytes
+	})
+}
+
+// Tests that hashing asymmetric objects without a dedicated hasher panics instead
+// of silently hashing them as empty containers.
+func TestHashUnhashable(t *testing.T) {
+	obj := &testUnhashable{Value: 1}
+	if _, err := ssz.Marshal(obj); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("asymmetric object without hasher hashed")
+		}
+	}()
+	ssz.HashSequential(obj)
+}
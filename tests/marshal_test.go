@@ -0,0 +1,71 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// Tests that the appending and allocating encoders produce the same output as
+// encoding into a pre-sized buffer.
+func TestMarshal(t *testing.T) {
+	testMarshal[*types.BeaconBlock](t)
+	testMarshal[*types.ExecutionPayload](t)
+	testMarshal[*types.Withdrawal](t)
+	testMarshal[*testDerivedBinaries](t)
+	testMarshal[*testDerivedUints](t)
+}
+
+func testMarshal[T newableObject[U], U any](t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 4; i++ {
+		obj := T(new(U))
+		fillRandom(rng, reflect.ValueOf(obj).Elem(), "")
+
+		want := make([]byte, ssz.Size(obj))
+		if err := ssz.EncodeToBytes(want, obj); err != nil {
+			t.Fatalf("%T: failed to encode: %v", obj, err)
+		}
+		blob, err := ssz.Marshal(obj)
+		if err != nil {
+			t.Fatalf("%T: failed to marshal: %v", obj, err)
+		}
+		if !bytes.Equal(blob, want) {
+			t.Fatalf("%T: marshal mismatch: have %x, want %x", obj, blob, want)
+		}
+		header := []byte{0xde, 0xad, 0xbe, 0xef}
+		blob, err = ssz.EncodeAppend(header, obj)
+		if err != nil {
+			t.Fatalf("%T: failed to append: %v", obj, err)
+		}
+		if !bytes.Equal(blob[:len(header)], header) || !bytes.Equal(blob[len(header):], want) {
+			t.Fatalf("%T: append mismatch: have %x, want %x%x", obj, blob, header, want)
+		}
+	}
+}
+
+// Tests that appending an object misreporting its size fails without extending
+// the destination slice.
+func TestEncodeAppendError(t *testing.T) {
+	header := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	blob, err := ssz.EncodeAppend(header, new(testBrokenStatic))
+	if !errors.Is(err, ssz.ErrShortBuffer) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(blob, header) {
+		t.Fatalf("destination modified: have %x, want %x", blob, header)
+	}
+	if blob, err := ssz.Marshal(new(testBrokenStatic)); err == nil || blob != nil {
+		t.Fatalf("marshalled broken object: %x, %v", blob, err)
+	}
+}
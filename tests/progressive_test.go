@@ -0,0 +1,182 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// testProgressiveItem is a ProgressiveContainer with an inactive field.
+type testProgressiveItem struct {
+	Slot  uint64
+	Data  []byte
+	Flags uint8
+}
+
+func (t *testProgressiveItem) SizeSSZ(fixed bool) uint32 {
+	size := uint32(8 + 4 + 1)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeDynamicBytes(t.Data)
+	return size
+}
+func (t *testProgressiveItem) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineProgressiveContainer(codec, []byte{0x0d}, func(codec *ssz.Codec) {
+		// Define the static data (fields and dynamic offsets)
+		ssz.DefineUint64(codec, &t.Slot)                                // Field  (0) -  Slot - 8 bytes
+		ssz.DefineProgressiveDynamicBytesOffset(codec.Skip(1), &t.Data) // Offset (2) -  Data - 4 bytes
+		ssz.DefineUint8(codec, &t.Flags)                                // Field  (3) - Flags - 1 byte
+
+		// Define the dynamic data (fields)
+		ssz.DefineProgressiveDynamicBytesContent(codec, &t.Data) // Field  (2) -  Data - ? bytes
+	})
+}
+
+// testProgressive is a container with progressive lists of all supported kinds.
+type testProgressive struct {
+	Bits        []byte
+	Uint64s     []uint64
+	Bools       []bool
+	Hashes      [][32]byte
+	Blobs       [][]byte
+	Checkpoints []*types.Checkpoint
+	Items       []*testProgressiveItem
+}
+
+func (t *testProgressive) SizeSSZ(fixed bool) uint32 {
+	size := uint32(7 * 4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeBitlist(t.Bits)
+	size += ssz.SizeSliceOfUint64s(t.Uint64s)
+	size += ssz.SizeSliceOfBools(t.Bools)
+	size += ssz.SizeSliceOfStaticBytes(t.Hashes)
+	size += ssz.SizeSliceOfDynamicBytes(t.Blobs)
+	size += ssz.SizeSliceOfStaticObjects(t.Checkpoints)
+	size += ssz.SizeSliceOfDynamicObjects(t.Items)
+	return size
+}
+func (t *testProgressive) DefineSSZ(codec *ssz.Codec) {
+	// Define the static data (fields and dynamic offsets)
+	ssz.DefineProgressiveBitlistOffset(codec, &t.Bits)                    // Offset (0) -        Bits - 4 bytes
+	ssz.DefineProgressiveListOfUint64sOffset(codec, &t.Uint64s)           // Offset (1) -     Uint64s - 4 bytes
+	ssz.DefineProgressiveListOfBoolsOffset(codec, &t.Bools)               // Offset (2) -       Bools - 4 bytes
+	ssz.DefineProgressiveListOfStaticBytesOffset(codec, &t.Hashes)        // Offset (3) -      Hashes - 4 bytes
+	ssz.DefineProgressiveListOfDynamicBytesOffset(codec, &t.Blobs)        // Offset (4) -       Blobs - 4 bytes
+	ssz.DefineProgressiveListOfStaticObjectsOffset(codec, &t.Checkpoints) // Offset (5) - Checkpoints - 4 bytes
+	ssz.DefineProgressiveListOfDynamicObjectsOffset(codec, &t.Items)      // Offset (6) -       Items - 4 bytes
+
+	// Define the dynamic data (fields)
+	ssz.DefineProgressiveBitlistContent(codec, &t.Bits)                    // Field  (0) -        Bits - ? bytes
+	ssz.DefineProgressiveListOfUint64sContent(codec, &t.Uint64s)           // Field  (1) -     Uint64s - ? bytes
+	ssz.DefineProgressiveListOfBoolsContent(codec, &t.Bools)               // Field  (2) -       Bools - ? bytes
+	ssz.DefineProgressiveListOfStaticBytesContent(codec, &t.Hashes)        // Field  (3) -      Hashes - ? bytes
+	ssz.DefineProgressiveListOfDynamicBytesContent(codec, &t.Blobs, 16)    // Field  (4) -       Blobs - ? bytes
+	ssz.DefineProgressiveListOfStaticObjectsContent(codec, &t.Checkpoints) // Field  (5) - Checkpoints - ? bytes
+	ssz.DefineProgressiveListOfDynamicObjectsContent(codec, &t.Items)      // Field  (6) -       Items - ? bytes
+}
+
+// newTestProgressive creates a progressive list container with the given number
+// of items in each list.
+func newTestProgressive(n int) *testProgressive {
+	obj := &testProgressive{
+		Bits: make([]byte, n/8+1),
+	}
+	obj.Bits[n/8] |= 1 << (n % 8)
+	for i := 0; i < n; i++ {
+		obj.Bits[i/8] |= byte(i%3/2) << (i % 8)
+		obj.Uint64s = append(obj.Uint64s, uint64(i+1))
+		obj.Bools = append(obj.Bools, i%2 == 0)
+		obj.Hashes = append(obj.Hashes, [32]byte{byte(i + 1)})
+		obj.Blobs = append(obj.Blobs, bytes.Repeat([]byte{byte(i + 1)}, i%16+1))
+		obj.Checkpoints = append(obj.Checkpoints, &types.Checkpoint{Epoch: uint64(i + 1), Root: types.Hash{byte(i + 1)}})
+		obj.Items = append(obj.Items, &testProgressiveItem{Slot: uint64(i + 1), Data: bytes.Repeat([]byte{byte(i + 1)}, i%5+1), Flags: byte(i)})
+	}
+	return obj
+}
+
+// Tests that progressive lists and containers can be encoded, decoded and hashed.
+func TestProgressive(t *testing.T) {
+	tests := []struct {
+		items int
+		root  string
+	}{
+		{0, "dfeb527fd040eb799f2325b63323cb3df722bec679d87bf63ff05be9333a0729"},
+		{1, "cd7acabeafb5ee02aa23571dfc70f886c92960cf98848c2bd5a60a12e981a076"},
+		{5, "ec16e5d069923f84c28db6eb4b31a49e976b8eb7da1349d159bea20f9d611baa"},
+		{30, "5a13d7a7bd9eecc0845e3b37b63f91a23c9adc8ca46817613ae08bc5673b220f"},
+		{100, "4dfc8b202adc48988b1736fbd940cd955bf022175a70ad1f1099d35951191740"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.items), func(t *testing.T) {
+			testRoundTrip(t, newTestProgressive(tt.items), nil, tt.root)
+		})
+	}
+}
+
+// Tests that the decoder's safety limit is enforced on progressive lists.
+func TestProgressiveLimit(t *testing.T) {
+	blob := new(bytes.Buffer)
+	if err := ssz.EncodeToStream(blob, newTestProgressive(10)); err != nil {
+		t.Fatalf("failed to encode stream: %v", err)
+	}
+	if err := ssz.DecodeFromBytesWithLimit(blob.Bytes(), new(testProgressive), 10); err != nil {
+		t.Fatalf("failed to decode at limit: %v", err)
+	}
+	if err := ssz.DecodeFromBytesWithLimit(blob.Bytes(), new(testProgressive), 9); !errors.Is(err, ssz.ErrMaxItemsExceeded) {
+		t.Errorf("buffer error mismatch: have %v, want %v", err, ssz.ErrMaxItemsExceeded)
+	}
+	if err := ssz.DecodeFromStreamWithLimit(bytes.NewReader(blob.Bytes()), new(testProgressive), uint32(blob.Len()), 9); !errors.Is(err, ssz.ErrMaxItemsExceeded) {
+		t.Errorf("stream error mismatch: have %v, want %v", err, ssz.ErrMaxItemsExceeded)
+	}
+}
+
+// Tests that generalized indices resolve into progressive lists and containers,
+// and that the proofs verify against the root computed by the hasher.
+func TestProveProgressive(t *testing.T) {
+	obj := newTestProgressive(30)
+	root := ssz.HashSequential(obj)
+
+	for _, index := range []int{0, 1, 4, 5, 20, 21, 29} {
+		// Uint64s is the 2nd field, with 4 items packed per chunk
+		gindex, err := ssz.GeneralizedIndex(obj, 1, index)
+		if err != nil {
+			t.Fatalf("item %d: failed to resolve generalized index: %v", index, err)
+		}
+		leaf, branch, err := ssz.Prove(obj, gindex)
+		if err != nil {
+			t.Fatalf("item %d: failed to generate proof: %v", index, err)
+		}
+		if leaf[index%4*8] != byte(index+1) {
+			t.Errorf("item %d: proven leaf mismatch: have %x", index, leaf)
+		}
+		if !ssz.VerifyProof(root, leaf, branch, gindex) {
+			t.Errorf("item %d: failed to verify proof", index)
+		}
+		// Also prove the flags of the matching progressive container item, which
+		// is the 7th field
+		gindex, err = ssz.GeneralizedIndex(obj, 6, index, 3)
+		if err != nil {
+			t.Fatalf("item %d: failed to resolve container generalized index: %v", index, err)
+		}
+		if leaf, branch, err = ssz.Prove(obj, gindex); err != nil {
+			t.Fatalf("item %d: failed to generate container proof: %v", index, err)
+		}
+		if leaf[0] != byte(index) {
+			t.Errorf("item %d: proven container leaf mismatch: have %x", index, leaf)
+		}
+		if !ssz.VerifyProof(root, leaf, branch, gindex) {
+			t.Errorf("item %d: failed to verify container proof", index)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// Tests that Merkle proofs can be generated for static fields of a container
+// and that they verify against the root computed by the hasher.
+func TestProveStaticField(t *testing.T) {
+	header := &types.BeaconBlockHeader{
+		Slot:          1,
+		ProposerIndex: 2,
+		ParentRoot:    types.Hash{0x03},
+		StateRoot:     types.Hash{0x04},
+		BodyRoot:      types.Hash{0x05},
+	}
+	root := ssz.HashSequential(header)
+
+	// 5 fields, padded to 8 leaves, StateRoot is the 4th one
+	leaf, branch, err := ssz.Prove(header, 8+3)
+	if err != nil {
+		t.Fatalf("failed to generate proof: %v", err)
+	}
+	if leaf != header.StateRoot {
+		t.Errorf("proven leaf mismatch: have %x, want %x", leaf, header.StateRoot)
+	}
+	if len(branch) != 3 {
+		t.Errorf("proof branch length mismatch: have %d, want %d", len(branch), 3)
+	}
+	if !ssz.VerifyProof(root, leaf, branch, 8+3) {
+		t.Errorf("failed to verify proof")
+	}
+	if ssz.VerifyProof(root, leaf, branch, 8+4) {
+		t.Errorf("verified proof for wrong index")
+	}
+}
+
+// Tests that Merkle proofs can be generated for fields nested within dynamic
+// lists of objects.
+func TestProveDynamicField(t *testing.T) {
+	payload := &types.ExecutionPayloadCapella{
+		ExtraData:     []byte{0x01, 0x02},
+		BaseFeePerGas: uint256.NewInt(7),
+		Transactions:  [][]byte{{0x03}, {0x04, 0x05}},
+		Withdrawals: []*types.Withdrawal{
+			{Index: 1, Amount: 100},
+			{Index: 2, Amount: 200},
+		},
+	}
+	root := ssz.HashSequential(payload)
+
+	// 15 fields padded to 16 leaves, withdrawals is the 15th one; the data root
+	// of the list is the left child, with 16 items max; amount is the 4th field
+	// of 4 in a withdrawal.
+	gindex := uint64(((16+14)*2*16+1)*4 + 3)
+
+	leaf, branch, err := ssz.Prove(payload, gindex)
+	if err != nil {
+		t.Fatalf("failed to generate proof: %v", err)
+	}
+	if amount := binary.LittleEndian.Uint64(leaf[:8]); amount != 200 {
+		t.Errorf("proven leaf mismatch: have %d, want %d", amount, 200)
+	}
+	if !ssz.VerifyProof(root, leaf, branch, gindex) {
+		t.Errorf("failed to verify proof")
+	}
+	// Prove the list length mix-in too
+	leaf, branch, err = ssz.Prove(payload, (16+14)*2+1)
+	if err != nil {
+		t.Fatalf("failed to generate proof: %v", err)
+	}
+	if length := binary.LittleEndian.Uint64(leaf[:8]); length != 2 {
+		t.Errorf("proven length mismatch: have %d, want %d", length, 2)
+	}
+	if !ssz.VerifyProof(root, leaf, branch, (16+14)*2+1) {
+		t.Errorf("failed to verify proof")
+	}
+}
+
+// Tests that proofs are generated for every node of the tree, including the
+// padding ones, but requesting anything beyond the leaves fails.
+func TestProveAllIndices(t *testing.T) {
+	exit := &types.SignedVoluntaryExit{
+		Exit:      &types.VoluntaryExit{Epoch: 1, ValidatorIndex: 2},
+		Signature: [96]byte{0x03},
+	}
+	root := ssz.HashSequential(exit)
+
+	// SignedVoluntaryExit has the exit at index 2 (subtree of 2 leaves) and the
+	// signature at index 3 (subtree of 4 leaves, padded from 3)
+	for _, gindex := range []uint64{1, 2, 3, 4, 5, 6, 7, 12, 13, 14, 15} {
+		leaf, branch, err := ssz.Prove(exit, gindex)
+		if err != nil {
+			t.Errorf("gindex %d: failed to generate proof: %v", gindex, err)
+			continue
+		}
+		if !ssz.VerifyProof(root, leaf, branch, gindex) {
+			t.Errorf("gindex %d: failed to verify proof", gindex)
+		}
+	}
+	for _, gindex := range []uint64{0, 8, 9, 24} {
+		if _, _, err := ssz.Prove(exit, gindex); !errors.Is(err, ssz.ErrInvalidGeneralizedIndex) {
+			t.Errorf("gindex %d: error mismatch: have %v, want %v", gindex, err, ssz.ErrInvalidGeneralizedIndex)
+		}
+	}
+}
+
+// Tests that Merkle multiproofs can be generated for multiple fields at once,
+// deduplicating the shared sibling hashes, and that they verify.
+func TestProveMulti(t *testing.T) {
+	body := &types.BeaconBlockBody{
+		RandaoReveal: [96]byte{0x01},
+		Eth1Data:     &types.Eth1Data{DepositCount: 2},
+		Graffiti:     [32]byte{0x03},
+		Attestations: []*types.Attestation{
+			{
+				AggregationBits: []byte{0x01},
+				Data: &types.AttestationData{
+					Source: new(types.Checkpoint),
+					Target: new(types.Checkpoint),
+				},
+			},
+		},
+		VoluntaryExits: []*types.SignedVoluntaryExit{
+			{Exit: &types.VoluntaryExit{Epoch: 4}},
+		},
+	}
+	root := ssz.HashSequential(body)
+
+	// 8 fields, graffiti is the 3rd, attestations the 6th and voluntary exits the
+	// 8th (length mix-in proven)
+	gindices := []uint64{8 + 2, (8+5)*2*128 + 0, (8+7)*2 + 1}
+
+	leaves, proof, err := ssz.ProveMulti(body, gindices)
+	if err != nil {
+		t.Fatalf("failed to generate multiproof: %v", err)
+	}
+	if leaves[0] != body.Graffiti {
+		t.Errorf("proven graffiti mismatch: have %x, want %x", leaves[0], body.Graffiti)
+	}
+	if leaves[1] != ssz.HashSequential(body.Attestations[0]) {
+		t.Errorf("proven attestation mismatch: have %x, want %x", leaves[1], ssz.HashSequential(body.Attestations[0]))
+	}
+	if !ssz.VerifyMulti(root, leaves, proof, gindices) {
+		t.Errorf("failed to verify multiproof")
+	}
+	// Ensure the multiproof is smaller than the individual proofs
+	var single int
+	for _, gindex := range gindices {
+		_, branch, err := ssz.Prove(body, gindex)
+		if err != nil {
+			t.Fatalf("failed to generate proof: %v", err)
+		}
+		single += len(branch)
+	}
+	if len(proof) >= single {
+		t.Errorf("multiproof not deduplicated: have %d hashes, single proofs %d", len(proof), single)
+	}
+	// Ensure tampering with the leaves or proof fails verification
+	leaves[0][0]++
+	if ssz.VerifyMulti(root, leaves, proof, gindices) {
+		t.Errorf("verified multiproof with tampered leaf")
+	}
+	leaves[0][0]--
+	if ssz.VerifyMulti(root, leaves, proof[1:], gindices) {
+		t.Errorf("verified multiproof with missing helper")
+	}
+}
+
+// Tests that field paths are correctly resolved into generalized indices.
+func TestGeneralizedIndex(t *testing.T) {
+	block := &types.BeaconBlock{
+		Body: &types.BeaconBlockBody{
+			Eth1Data: new(types.Eth1Data),
+			Attestations: []*types.Attestation{
+				{
+					Data: &types.AttestationData{
+						Source: new(types.Checkpoint),
+						Target: &types.Checkpoint{Epoch: 1, Root: types.Hash{0x02}},
+					},
+				},
+			},
+		},
+	}
+	payload := &types.ExecutionPayloadCapella{
+		Transactions: [][]byte{{0x01}},
+		Withdrawals: []*types.Withdrawal{
+			{Index: 1, Amount: 100},
+			{Index: 2, Amount: 200},
+		},
+	}
+	tests := []struct {
+		obj    ssz.Object
+		path   []any
+		gindex uint64
+	}{
+		{block, []any{"state_root"}, 8 + 3},
+		{block, []any{3}, 8 + 3},
+		{block, []any{"body", "graffiti"}, (8+4)*8 + 2},
+		{block, []any{"body", "attestations", "__len__"}, ((8+4)*8+5)*2 + 1},
+		{block, []any{"body", "attestations", 0, "data", "target", "root"}, (((((8+4)*8+5)*2*128+0)*4+1)*8+4)*2 + 1},
+		{block, []any{"body", "attestations", 127}, (((8+4)*8+5)*2*128 + 127)},
+		{payload, []any{"withdrawals", 1, "amount"}, ((16+14)*2*16+1)*4 + 3},
+		{payload, []any{"extra_data", 5}, (16 + 10) * 2},
+		{payload, []any{"transactions", 0, 40}, ((16+13)*2*1048576+0)*2*33554432 + 1},
+	}
+	for i, tt := range tests {
+		gindex, err := ssz.GeneralizedIndex(tt.obj, tt.path...)
+		if err != nil {
+			t.Errorf("test %d: failed to resolve path %v: %v", i, tt.path, err)
+			continue
+		}
+		if gindex != tt.gindex {
+			t.Errorf("test %d: gindex mismatch for %v: have %d, want %d", i, tt.path, gindex, tt.gindex)
+		}
+	}
+	// Ensure a resolved path can be proven
+	gindex, err := ssz.GeneralizedIndex(block, "body", "attestations", 0, "data", "target", "root")
+	if err != nil {
+		t.Fatalf("failed to resolve path: %v", err)
+	}
+	leaf, _, err := ssz.Prove(block, gindex)
+	if err != nil {
+		t.Fatalf("failed to generate proof: %v", err)
+	}
+	if leaf != block.Body.Attestations[0].Data.Target.Root {
+		t.Errorf("proven leaf mismatch: have %x, want %x", leaf, block.Body.Attestations[0].Data.Target.Root)
+	}
+	// Ensure invalid paths are rejected
+	invalids := []struct {
+		obj  ssz.Object
+		path []any
+	}{
+		{block, []any{"unknown"}},
+		{block, []any{5}},
+		{block, []any{"slot", "epoch"}},
+		{block, []any{"body", "__len__"}},
+		{block, []any{"body", "attestations", 128}},
+		{block, []any{"body", "attestations", 1, "data"}},
+		{block, []any{"body", -1}},
+		{block, []any{"body", 1.5}},
+	}
+	for i, tt := range invalids {
+		if _, err := ssz.GeneralizedIndex(tt.obj, tt.path...); !errors.Is(err, ssz.ErrInvalidFieldPath) {
+			t.Errorf("test %d: error mismatch for %v: have %v, want %v", i, tt.path, err, ssz.ErrInvalidFieldPath)
+		}
+	}
+}
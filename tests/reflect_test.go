@@ -0,0 +1,129 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// Tests that the reflection based codec is byte-for-byte compatible with the
+// Codec based one, on randomly filled objects of all the test types.
+func TestReflect(t *testing.T) {
+	testReflect[*types.Attestation](t)
+	testReflect[*types.AttestationData](t)
+	testReflect[*types.AttesterSlashing](t)
+	testReflect[*types.BeaconBlock](t)
+	testReflect[*types.BeaconBlockBody](t)
+	testReflect[*types.BeaconBlockHeader](t)
+	testReflect[*types.Checkpoint](t)
+	testReflect[*types.Deposit](t)
+	testReflect[*types.DepositData](t)
+	testReflect[*types.Eth1Data](t)
+	testReflect[*types.ExecutionPayload](t)
+	testReflect[*types.ExecutionPayloadCapella](t)
+	testReflect[*types.HistoricalBatch](t)
+	testReflect[*types.IndexedAttestation](t)
+	testReflect[*types.ProposerSlashing](t)
+	testReflect[*types.SignedBeaconBlockHeader](t)
+	testReflect[*types.SignedVoluntaryExit](t)
+	testReflect[*types.SyncAggregate](t)
+	testReflect[*types.Validator](t)
+	testReflect[*types.VoluntaryExit](t)
+	testReflect[*types.Withdrawal](t)
+	testReflect[*testArrays](t)
+	testReflect[*testBinaries](t)
+	testReflect[*testBools](t)
+	testReflect[*testUints](t)
+}
+
+func testReflect[T newableObject[U], U any](t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 16; i++ {
+		obj := T(new(U))
+		fillRandom(rng, reflect.ValueOf(obj).Elem(), "")
+
+		// Encode the object with both codecs and cross check them
+		want := make([]byte, ssz.Size(obj))
+		if err := ssz.EncodeToBytes(want, obj); err != nil {
+			t.Fatalf("%T: failed to encode with codec: %v", obj, err)
+		}
+		if size := ssz.SizeReflect(obj); int(size) != len(want) {
+			t.Fatalf("%T: size mismatch: reflect %d, codec %d", obj, size, len(want))
+		}
+		have := make([]byte, len(want))
+		if err := ssz.EncodeReflect(have, obj); err != nil {
+			t.Fatalf("%T: failed to encode with reflection: %v", obj, err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Fatalf("%T: encoding mismatch:\nreflect: %x\ncodec:   %x", obj, have, want)
+		}
+		// Decode the blob with both codecs and cross check them
+		decCodec, decReflect := T(new(U)), T(new(U))
+		if err := ssz.DecodeFromBytes(want, decCodec); err != nil {
+			t.Fatalf("%T: failed to decode with codec: %v", obj, err)
+		}
+		if err := ssz.DecodeReflect(want, decReflect); err != nil {
+			t.Fatalf("%T: failed to decode with reflection: %v", obj, err)
+		}
+		if !reflect.DeepEqual(decReflect, decCodec) {
+			t.Fatalf("%T: decoding mismatch:\nreflect: %+v\ncodec:   %+v", obj, decReflect, decCodec)
+		}
+	}
+}
+
+// fillRandom populates a value with random data, honoring the ssz struct tags
+// so the result is a valid ssz object.
+func fillRandom(rng *rand.Rand, v reflect.Value, tag reflect.StructTag) {
+	bits := tag.Get("ssz") == "bits"
+	limit := 4
+	if max, ok := tag.Lookup("ssz-max"); ok {
+		items, _ := strconv.Atoi(strings.Split(max, ",")[0])
+		limit = min(limit, items)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(rng.Intn(2) == 1)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(rng.Uint64())
+	case reflect.Pointer:
+		if v.Type().Elem() == reflect.TypeOf(uint256.Int{}) {
+			v.Set(reflect.ValueOf(new(uint256.Int).SetUint64(rng.Uint64())))
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fillRandom(rng, v.Elem(), "")
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillRandom(rng, v.Index(i), "")
+		}
+	case reflect.Slice:
+		n := rng.Intn(limit + 1)
+		if bits {
+			n++ // bitlists need at least the delimiter byte
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			fillRandom(rng, v.Index(i), "")
+		}
+		if bits {
+			v.Index(n - 1).SetUint(uint64(rng.Intn(255) + 1))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() && field.Tag.Get("ssz") != "-" {
+				fillRandom(rng, v.Field(i), field.Tag)
+			}
+		}
+	}
+}
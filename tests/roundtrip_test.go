@@ -0,0 +1,57 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/karalabe/ssz"
+)
+
+// testRoundTrip checks that an object encodes the same way in streaming and in
+// buffered mode, into the size it reports (and into wantBlob, if set), that it
+// decodes back into an identical object in both modes and that it hashes into
+// wantRoot (hex encoded).
+func testRoundTrip(t *testing.T, obj ssz.Object, wantBlob []byte, wantRoot string) {
+	t.Helper()
+
+	blob := new(bytes.Buffer)
+	if err := ssz.EncodeToStream(blob, obj); err != nil {
+		t.Fatalf("failed to encode stream: %v", err)
+	}
+	if size := ssz.Size(obj); int(size) != blob.Len() {
+		t.Fatalf("size mismatch: reported %d, encoded %d", size, blob.Len())
+	}
+	if wantBlob != nil && !bytes.Equal(blob.Bytes(), wantBlob) {
+		t.Fatalf("stream encoding mismatch: have %x, want %x", blob.Bytes(), wantBlob)
+	}
+	buf := make([]byte, blob.Len())
+	if err := ssz.EncodeToBytes(buf, obj); err != nil {
+		t.Fatalf("failed to encode buffer: %v", err)
+	}
+	if !bytes.Equal(buf, blob.Bytes()) {
+		t.Fatalf("buffer encoding mismatch: have %x, want %x", buf, blob.Bytes())
+	}
+	dec := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(ssz.Object)
+	if err := ssz.DecodeFromStream(bytes.NewReader(blob.Bytes()), dec, uint32(blob.Len())); err != nil {
+		t.Fatalf("failed to decode stream: %v", err)
+	}
+	if !reflect.DeepEqual(dec, obj) {
+		t.Fatalf("stream decoding mismatch: have %+v, want %+v", dec, obj)
+	}
+	dec = reflect.New(reflect.TypeOf(obj).Elem()).Interface().(ssz.Object)
+	if err := ssz.DecodeFromBytes(blob.Bytes(), dec); err != nil {
+		t.Fatalf("failed to decode buffer: %v", err)
+	}
+	if !reflect.DeepEqual(dec, obj) {
+		t.Fatalf("buffer decoding mismatch: have %+v, want %+v", dec, obj)
+	}
+	if hash := fmt.Sprintf("%x", ssz.HashSequential(obj)); hash != wantRoot {
+		t.Errorf("root mismatch: have %s, want %s", hash, wantRoot)
+	}
+}
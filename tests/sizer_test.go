@@ -0,0 +1,226 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// testDerivedBinaries is a testBinaries without SizeSSZ, to be measured from its
+// schema.
+type testDerivedBinaries testBinaries
+
+func (t *testDerivedBinaries) DefineSSZ(codec *ssz.Codec) { (*testBinaries)(t).DefineSSZ(codec) }
+
+// testDerivedUints is a testUints without SizeSSZ, to be measured from its schema.
+type testDerivedUints testUints
+
+func (t *testDerivedUints) DefineSSZ(codec *ssz.Codec) { (*testUints)(t).DefineSSZ(codec) }
+
+// Tests that the sizes derived from the schemas match the hand written and the
+// generated SizeSSZ methods.
+func TestDeriveSize(t *testing.T) {
+	testDeriveSize[*types.Attestation](t)
+	testDeriveSize[*types.AttestationData](t)
+	testDeriveSize[*types.AttesterSlashing](t)
+	testDeriveSize[*types.BeaconBlock](t)
+	testDeriveSize[*types.BeaconBlockBody](t)
+	testDeriveSize[*types.BeaconBlockHeader](t)
+	testDeriveSize[*types.Checkpoint](t)
+	testDeriveSize[*types.Deposit](t)
+	testDeriveSize[*types.DepositData](t)
+	testDeriveSize[*types.Eth1Data](t)
+	testDeriveSize[*types.ExecutionPayload](t)
+	testDeriveSize[*types.ExecutionPayloadCapella](t)
+	testDeriveSize[*types.IndexedAttestation](t)
+	testDeriveSize[*types.ProposerSlashing](t)
+	testDeriveSize[*types.SignedBeaconBlockHeader](t)
+	testDeriveSize[*types.SignedVoluntaryExit](t)
+	testDeriveSize[*types.SyncAggregate](t)
+	testDeriveSize[*types.Validator](t)
+	testDeriveSize[*types.VoluntaryExit](t)
+	testDeriveSize[*types.Withdrawal](t)
+	testDeriveSize[*testArrays](t)
+	testDeriveSize[*testBinaries](t)
+	testDeriveSize[*testBools](t)
+	testDeriveSize[*testUints](t)
+
+	// Containers with value dependent layouts can only be checked in full
+	u8 := func(n uint8) *uint8 { return &n }
+	u16 := func(n uint16) *uint16 { return &n }
+	u64 := func(n uint64) *uint64 { return &n }
+
+	objs := []ssz.Object{
+		&testShape{Side: u16(0x42), Color: u8(1)},
+		&testShape{},
+		&testSquare{Side: u16(0x42), Color: u8(1)},
+		&testStableDynamic{Slot: u64(7), Data: []byte{1, 2, 3}, Root: &[32]byte{0x02}},
+		&testProfileDynamic{Slot: u64(7), Data: []byte{1, 2, 3}, Root: &[32]byte{0x02}},
+		&testUnion{},
+		&testUnion{Selector: 1, Value: &types.Checkpoint{}},
+		&testUnion{Selector: 2, Value: &types.Attestation{AggregationBits: []byte{0x03, 0x01}, Data: &types.AttestationData{Source: new(types.Checkpoint), Target: new(types.Checkpoint)}}},
+		newTestProgressive(0),
+		newTestProgressive(30),
+	}
+	for i, obj := range objs {
+		if have, want := ssz.DeriveSize(obj, false), ssz.Size(obj); have != want {
+			t.Errorf("test %d: %T: size mismatch: derived %d, declared %d", i, obj, have, want)
+		}
+	}
+}
+
+func testDeriveSize[T newableObject[U], U any](t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 16; i++ {
+		obj := T(new(U))
+		fillRandom(rng, reflect.ValueOf(obj).Elem(), "")
+
+		if have, want := ssz.DeriveSize(obj, false), ssz.Size(obj); have != want {
+			t.Fatalf("%T: size mismatch: derived %d, declared %d", obj, have, want)
+		}
+		if err := ssz.Validate(obj); err != nil {
+			t.Fatalf("%T: failed to validate: %v", obj, err)
+		}
+		want := ssz.Size(obj)
+		if v, ok := any(obj).(ssz.DynamicObject); ok {
+			want = v.SizeSSZ(true)
+		}
+		if have := ssz.DeriveSize(obj, true); have != want {
+			t.Fatalf("%T: static size mismatch: derived %d, declared %d", obj, have, want)
+		}
+	}
+}
+
+// Tests that objects implementing only DefineSSZ can be encoded, decoded and
+// hashed the same way as their counterparts with SizeSSZ.
+func TestDerivedObjects(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 16; i++ {
+		dynamic, static := new(testBinaries), new(testUints)
+		fillRandom(rng, reflect.ValueOf(dynamic).Elem(), "")
+		fillRandom(rng, reflect.ValueOf(static).Elem(), "")
+
+		testDerivedObject(t, dynamic, (*testDerivedBinaries)(dynamic), new(testDerivedBinaries))
+		testDerivedObject(t, static, (*testDerivedUints)(static), new(testDerivedUints))
+	}
+	// Asymmetric objects cannot be measured from their schemas
+	defer func() {
+		if recover() == nil {
+			t.Errorf("asymmetric object measured")
+		}
+	}()
+	ssz.DeriveSize(new(types.HistoricalBatch), false)
+}
+
+func testDerivedObject(t *testing.T, obj ssz.Object, derived ssz.Object, dec ssz.Object) {
+	want := make([]byte, ssz.Size(obj))
+	if err := ssz.EncodeToBytes(want, obj); err != nil {
+		t.Fatalf("%T: failed to encode: %v", obj, err)
+	}
+	if size := ssz.Size(derived); int(size) != len(want) {
+		t.Fatalf("%T: size mismatch: have %d, want %d", derived, size, len(want))
+	}
+	have := new(bytes.Buffer)
+	if err := ssz.EncodeToStream(have, derived); err != nil {
+		t.Fatalf("%T: failed to encode: %v", derived, err)
+	}
+	if !bytes.Equal(have.Bytes(), want) {
+		t.Fatalf("%T: encoding mismatch: have %x, want %x", derived, have, want)
+	}
+	if err := ssz.DecodeFromBytes(want, dec); err != nil {
+		t.Fatalf("%T: failed to decode: %v", dec, err)
+	}
+	blob := make([]byte, ssz.Size(dec))
+	if err := ssz.EncodeToBytes(blob, dec); err != nil {
+		t.Fatalf("%T: failed to re-encode: %v", dec, err)
+	}
+	if !bytes.Equal(blob, want) {
+		t.Fatalf("%T: re-encoding mismatch: have %x, want %x", dec, blob, want)
+	}
+	if have, want := ssz.HashSequential(derived), ssz.HashSequential(obj); have != want {
+		t.Fatalf("%T: root mismatch: have %x, want %x", derived, have, want)
+	}
+}
+
+// testBrokenStatic declares 4 bytes less than its schema defines.
+type testBrokenStatic struct {
+	Slot uint64
+	Root [32]byte
+}
+
+func (t *testBrokenStatic) SizeSSZ() uint32 { return 36 }
+func (t *testBrokenStatic) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("slot"), &t.Slot)
+	ssz.DefineStaticBytes(codec.Named("root"), t.Root[:])
+}
+
+// testBrokenDynamic declares a static section 4 bytes larger than its schema
+// defines, and nests broken static objects.
+type testBrokenDynamic struct {
+	Data  []byte
+	Items []*testBrokenStatic
+}
+
+func (t *testBrokenDynamic) SizeSSZ(fixed bool) uint32 {
+	size := uint32(12)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeDynamicBytes(t.Data)
+	size += ssz.SizeSliceOfStaticObjects(t.Items)
+	return size
+}
+func (t *testBrokenDynamic) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineDynamicBytesOffset(codec.Named("data"), &t.Data)
+	ssz.DefineSliceOfStaticObjectsOffset(codec.Named("items"), &t.Items)
+
+	ssz.DefineDynamicBytesContent(codec, &t.Data, 32)
+	ssz.DefineSliceOfStaticObjectsContent(codec, &t.Items, 4)
+}
+
+// Tests that declared sizes diverging from the schemas are detected and reported
+// at the offending field.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		obj ssz.Object
+		err string
+	}{
+		{new(types.Withdrawal), ""},
+		{&testUnion{Selector: 1, Value: &types.Checkpoint{}}, ""},
+		{newTestProgressive(5), ""},
+		{new(testShape), ""},
+		{new(types.HistoricalBatch), ""},
+		{
+			new(testBrokenStatic),
+			"ssz: declared size mismatch: *tests.testBrokenStatic: static size declared 36, derived 40, diverging at *tests.testBrokenStatic.root",
+		},
+		{
+			&testBrokenDynamic{Data: []byte{1}},
+			"ssz: declared size mismatch: *tests.testBrokenDynamic: static size declared 12, derived 8, diverging at *tests.testBrokenDynamic.data",
+		},
+		{
+			&testBrokenDynamic{Items: []*testBrokenStatic{{}, {}}},
+			"ssz: declared size mismatch: *tests.testBrokenDynamic.items[0]: static size declared 36, derived 40, diverging at *tests.testBrokenDynamic.items[0].root",
+		},
+	}
+	for i, tt := range tests {
+		err := ssz.Validate(tt.obj)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case tt.err != "" && err == nil:
+			t.Errorf("test %d: expected error, got none", i)
+		case tt.err != "" && (err.Error() != tt.err || !errors.Is(err, ssz.ErrSizeMismatch)):
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
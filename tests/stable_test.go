@@ -0,0 +1,236 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// testShape is the StableContainer[4] example from EIP-7495.
+type testShape struct {
+	Side   *uint16
+	Color  *uint8
+	Radius *uint16
+}
+
+func (t *testShape) SizeSSZ(fixed bool) uint32 {
+	size := uint32(1)
+	if t.Side != nil {
+		size += 2
+	}
+	if t.Color != nil {
+		size += 1
+	}
+	if t.Radius != nil {
+		size += 2
+	}
+	return size
+}
+func (t *testShape) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStableContainer(codec, 4, func(codec *ssz.Codec) {
+		ssz.DefineOptionalUint16(codec, &t.Side)   // Field (0) -   Side - 2 bytes
+		ssz.DefineOptionalUint8(codec, &t.Color)   // Field (1) -  Color - 1 byte
+		ssz.DefineOptionalUint16(codec, &t.Radius) // Field (2) - Radius - 2 bytes
+	})
+}
+
+// testSquare is the Square Profile of the testShape from EIP-7495.
+type testSquare struct {
+	Side  *uint16
+	Color *uint8
+}
+
+func (t *testSquare) SizeSSZ(fixed bool) uint32 {
+	return 3
+}
+func (t *testSquare) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineProfile(codec, 4, func(codec *ssz.Codec) {
+		ssz.DefineOptionalUint16(codec.Required(), &t.Side) // Field (0) -  Side - 2 bytes
+		ssz.DefineOptionalUint8(codec.Required(), &t.Color) // Field (1) - Color - 1 byte
+	})
+}
+
+// testCircle is the Circle Profile of the testShape from EIP-7495.
+type testCircle struct {
+	Color  *uint8
+	Radius *uint16
+}
+
+func (t *testCircle) SizeSSZ(fixed bool) uint32 {
+	return 3
+}
+func (t *testCircle) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineProfile(codec, 4, func(codec *ssz.Codec) {
+		ssz.DefineOptionalUint8(codec.Skip(1).Required(), &t.Color) // Field (1) -  Color - 1 byte
+		ssz.DefineOptionalUint16(codec.Required(), &t.Radius)       // Field (2) - Radius - 2 bytes
+	})
+}
+
+// testStableDynamic is a StableContainer[16] with static and dynamic fields.
+type testStableDynamic struct {
+	Slot       *uint64
+	Data       []byte
+	Checkpoint *types.Checkpoint
+	Root       *[32]byte
+}
+
+func (t *testStableDynamic) SizeSSZ(fixed bool) uint32 {
+	size := uint32(2)
+	if t.Slot != nil {
+		size += 8
+	}
+	if t.Data != nil {
+		size += 4 + uint32(len(t.Data))
+	}
+	if t.Checkpoint != nil {
+		size += t.Checkpoint.SizeSSZ()
+	}
+	if t.Root != nil {
+		size += 32
+	}
+	return size
+}
+func (t *testStableDynamic) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStableContainer(codec, 16, func(codec *ssz.Codec) {
+		// Define the static data (fields and dynamic offsets)
+		ssz.DefineOptionalUint64(codec, &t.Slot)             // Field  (0) -       Slot -  8 bytes
+		ssz.DefineOptionalDynamicBytesOffset(codec, &t.Data) // Offset (1) -       Data -  4 bytes
+		ssz.DefineOptionalStaticObject(codec, &t.Checkpoint) // Field  (2) - Checkpoint - 40 bytes
+		ssz.DefineOptionalStaticBytes(codec, &t.Root)        // Field  (3) -       Root - 32 bytes
+
+		// Define the dynamic data (fields)
+		ssz.DefineOptionalDynamicBytesContent(codec, &t.Data, 32) // Field  (1) -       Data - ? bytes
+	})
+}
+
+// testProfileDynamic is a Profile of testStableDynamic with a required slot and
+// an optional data field.
+type testProfileDynamic struct {
+	Slot *uint64
+	Data []byte
+	Root *[32]byte
+}
+
+func (t *testProfileDynamic) SizeSSZ(fixed bool) uint32 {
+	size := uint32(1 + 8)
+	if t.Data != nil {
+		size += 4 + uint32(len(t.Data))
+	}
+	if t.Root != nil {
+		size += 32
+	}
+	return size
+}
+func (t *testProfileDynamic) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineProfile(codec, 16, func(codec *ssz.Codec) {
+		// Define the static data (fields and dynamic offsets)
+		ssz.DefineOptionalUint64(codec.Required(), &t.Slot)   // Field  (0) - Slot -  8 bytes
+		ssz.DefineOptionalDynamicBytesOffset(codec, &t.Data)  // Offset (1) - Data -  4 bytes
+		ssz.DefineOptionalStaticBytes(codec.Skip(1), &t.Root) // Field  (3) - Root - 32 bytes
+
+		// Define the dynamic data (fields)
+		ssz.DefineOptionalDynamicBytesContent(codec, &t.Data, 32) // Field  (1) - Data - ? bytes
+	})
+}
+
+// Tests that StableContainers and Profiles can be encoded, decoded and hashed,
+// with Profiles hashing to the same root as their base containers.
+func TestStable(t *testing.T) {
+	u8 := func(n uint8) *uint8 { return &n }
+	u16 := func(n uint16) *uint16 { return &n }
+	u64 := func(n uint64) *uint64 { return &n }
+
+	tests := []struct {
+		obj  ssz.Object
+		blob string
+		root string
+	}{
+		{&testShape{Side: u16(0x42), Color: u8(1)}, "03420001", "bfdb6fda9d02805e640c0f5767b8d1bb9ff4211498a5e2d7c0f36e1b88ce57ff"},
+		{&testShape{Color: u8(1), Radius: u16(0x42)}, "06014200", "f66d2c38c8d2afbd409e86c529dff728e9a4208215ca20ee44e49c3d11e145d8"},
+		{&testShape{}, "00", "28ba1834a3a7b657460ce79fa3a1d909ab8828fd557659d4d0554a9bdbc0ec30"},
+		{&testSquare{Side: u16(0x42), Color: u8(1)}, "420001", "bfdb6fda9d02805e640c0f5767b8d1bb9ff4211498a5e2d7c0f36e1b88ce57ff"},
+		{&testCircle{Color: u8(1), Radius: u16(0x42)}, "014200", "f66d2c38c8d2afbd409e86c529dff728e9a4208215ca20ee44e49c3d11e145d8"},
+		{&testStableDynamic{}, "0000", "792930bbd5baac43bcc798ee49aa8185ef76bb3b44ba62b91d86ae569e4bb535"},
+		{&testStableDynamic{Slot: u64(7), Data: []byte{}}, "030007000000000000000c000000", "89a71057cec61912ee4a09ddae950886c5b602697b11c55c3d1ea743ff927d68"},
+		{&testStableDynamic{Data: []byte{1, 2, 3}, Checkpoint: &types.Checkpoint{Epoch: 5, Root: types.Hash{0x01}}}, "06002c00000005000000000000000100000000000000000000000000000000000000000000000000000000000000010203", "9ec3763b702ad198c1f48e473ae293175e90d840bfda5639156e19061e952ad0"},
+		{&testStableDynamic{Slot: u64(7), Data: []byte{1, 2, 3}, Root: &[32]byte{0x02}}, "0b0007000000000000002c0000000200000000000000000000000000000000000000000000000000000000000000010203", "1ed9dd3480bc88a7250be073623faf5d78d289f5a9cf608ba5de5dac10e1a6ef"},
+		{&testProfileDynamic{Slot: u64(7)}, "000700000000000000", "f8ca9aec1fad011686af2c68da0031c712cb37a818d7c3b8b36d2888da091705"},
+		{&testProfileDynamic{Slot: u64(7), Data: []byte{1, 2, 3}, Root: &[32]byte{0x02}}, "0307000000000000002c0000000200000000000000000000000000000000000000000000000000000000000000010203", "1ed9dd3480bc88a7250be073623faf5d78d289f5a9cf608ba5de5dac10e1a6ef"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			blob, err := hex.DecodeString(tt.blob)
+			if err != nil {
+				t.Fatalf("invalid test blob: %v", err)
+			}
+			testRoundTrip(t, tt.obj, blob, tt.root)
+		})
+	}
+}
+
+// Tests that StableContainers and Profiles with invalid active field bitvectors
+// or mismatching data are rejected.
+func TestStableInvalid(t *testing.T) {
+	tests := []struct {
+		obj  ssz.Object
+		blob []byte
+		err  error
+	}{
+		{new(testShape), []byte{}, ssz.ErrInvalidActiveFields},
+		{new(testShape), []byte{0x10}, ssz.ErrInvalidActiveFields},
+		{new(testShape), []byte{0x08}, ssz.ErrInvalidActiveFields},
+		{new(testShape), []byte{0x01, 0x42}, ssz.ErrInvalidActiveFields},
+		{new(testShape), []byte{0x01, 0x42, 0x00, 0x00}, ssz.ErrInvalidActiveFields},
+		{new(testSquare), []byte{0x42, 0x00}, ssz.ErrInvalidActiveFields},
+		{new(testSquare), []byte{0x42, 0x00, 0x01, 0x00}, ssz.ErrInvalidActiveFields},
+		{new(testProfileDynamic), []byte{0x02, 0x07, 0, 0, 0, 0, 0, 0, 0}, ssz.ErrInvalidActiveFields},
+		{new(testProfileDynamic), []byte{0x04, 0x07, 0, 0, 0, 0, 0, 0, 0}, ssz.ErrInvalidActiveFields},
+		{new(testProfileDynamic), []byte{0x01, 0x07, 0, 0, 0, 0, 0, 0, 0, 0x0b, 0, 0, 0}, ssz.ErrFirstOffsetMismatch},
+	}
+	for i, tt := range tests {
+		if err := ssz.DecodeFromStream(bytes.NewReader(tt.blob), tt.obj, uint32(len(tt.blob))); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: stream error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err := ssz.DecodeFromBytes(tt.blob, tt.obj); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: buffer error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that generalized indices resolve into StableContainers and Profiles the
+// same way, and that the proofs verify against the root computed by the hasher.
+func TestProveStable(t *testing.T) {
+	side, color := uint16(0x42), uint8(1)
+
+	for _, obj := range []ssz.Object{&testShape{Side: &side, Color: &color}, &testSquare{Side: &side, Color: &color}} {
+		root := ssz.HashSequential(obj)
+
+		// 4 fields capacity mixed in with the active fields, color is the 2nd one
+		gindex, err := ssz.GeneralizedIndex(obj, 1)
+		if err != nil {
+			t.Fatalf("%T: failed to resolve generalized index: %v", obj, err)
+		}
+		if gindex != 2*4+1 {
+			t.Errorf("%T: generalized index mismatch: have %d, want %d", obj, gindex, 2*4+1)
+		}
+		leaf, branch, err := ssz.Prove(obj, gindex)
+		if err != nil {
+			t.Fatalf("%T: failed to generate proof: %v", obj, err)
+		}
+		if leaf[0] != color {
+			t.Errorf("%T: proven leaf mismatch: have %x", obj, leaf)
+		}
+		if !ssz.VerifyProof(root, leaf, branch, gindex) {
+			t.Errorf("%T: failed to verify proof", obj)
+		}
+	}
+}
@@ -4,25 +4,10 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Attestation -out gen_attestation_ssz.go
 
 type Attestation struct {
-	AggregationBits []byte
-	Data            *AttestationData
-	Signature       [96]byte
-}
-
-func (a *Attestation) SizeSSZ(fixed bool) uint32 {
-	size := uint32(228)
-	if !fixed {
-		size += ssz.SizeDynamicBytes(a.AggregationBits)
-	}
-	return size
-}
-func (a *Attestation) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineDynamicBytesOffset(codec, &a.AggregationBits) // Offset (0) - AggregationBits -  4 bytes
-	ssz.DefineStaticObject(codec, &a.Data)                  // Field  (1) - Data            - 128 bytes
-	ssz.DefineStaticBytes(codec, a.Signature[:])            // Field  (2) - Signature       -  96 bytes
-
-	ssz.DefineDynamicBytesContent(codec, &a.AggregationBits, 2048) // Offset (0) - AggregationBits -  4 bytes
+	AggregationBits []byte           `json:"aggregation_bits" ssz:"bits" ssz-max:"2048"`
+	Data            *AttestationData `json:"data"`
+	Signature       [96]byte         `json:"signature" ssz-size:"96"`
 }
@@ -4,21 +4,12 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type AttestationData -out gen_attestation_data_ssz.go
 
 type AttestationData struct {
-	Slot            Slot
-	Index           uint64
-	BeaconBlockHash Hash
-	Source          *Checkpoint
-	Target          *Checkpoint
-}
-
-func (a *AttestationData) SizeSSZ() uint32 { return 128 }
-func (a *AttestationData) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineUint64(codec, &a.Slot)                   // Field (0) - Slot             -  8 bytes
-	ssz.DefineUint64(codec, &a.Index)                  // Field (1) - Index            -  8 bytes
-	ssz.DefineStaticBytes(codec, a.BeaconBlockHash[:]) // Field (2) - BeaconBlockHash  - 32 bytes
-	ssz.DefineStaticObject(codec, &a.Source)           // Field (3) - Source           - 40 bytes
-	ssz.DefineStaticObject(codec, &a.Target)           // Field (4) - Source           - 40 bytes
+	Slot            Slot        `json:"slot" ssz-size:"8"`
+	Index           uint64      `json:"index" ssz-size:"8"`
+	BeaconBlockHash Hash        `json:"beacon_block_root" ssz-size:"32"`
+	Source          *Checkpoint `json:"source"`
+	Target          *Checkpoint `json:"target"`
 }
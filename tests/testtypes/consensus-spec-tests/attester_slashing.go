@@ -4,25 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type AttesterSlashing -out gen_attester_slashing_ssz.go
 
 type AttesterSlashing struct {
 	Attestation1 *IndexedAttestation `json:"attestation_1"`
 	Attestation2 *IndexedAttestation `json:"attestation_2"`
 }
-
-func (a *AttesterSlashing) SizeSSZ(fixed bool) uint32 {
-	size := uint32(8)
-	if !fixed {
-		size += ssz.SizeDynamicObject(a.Attestation1)
-		size += ssz.SizeDynamicObject(a.Attestation2)
-	}
-	return size
-}
-func (a *AttesterSlashing) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineDynamicObjectOffset(codec, &a.Attestation1) // Offset (0) - Attestation1 - 4 bytes
-	ssz.DefineDynamicObjectOffset(codec, &a.Attestation2) // Offset (1) - Attestation2 - 4 bytes
-
-	ssz.DefineDynamicObjectContent(codec, &a.Attestation1) // Offset (0) - Attestation1 - 4 bytes
-	ssz.DefineDynamicObjectContent(codec, &a.Attestation2) // Offset (1) - Attestation2 - 4 bytes
-}
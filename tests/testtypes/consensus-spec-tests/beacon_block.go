@@ -1,28 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type BeaconBlock -out gen_beacon_block_ssz.go
 
 type BeaconBlock struct {
-	Slot          Slot
-	ProposerIndex uint64
-	ParentRoot    Hash
-	StateRoot     Hash
-	Body          *BeaconBlockBody
-}
-
-func (b *BeaconBlock) SizeSSZ(fixed bool) uint32 {
-	size := uint32(84)
-	if !fixed {
-		size += ssz.SizeDynamicObject(b.Body)
-	}
-	return size
-}
-func (b *BeaconBlock) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineUint64(codec, &b.Slot)
-	ssz.DefineUint64(codec, &b.ProposerIndex)
-	ssz.DefineStaticBytes(codec, b.ParentRoot[:])
-	ssz.DefineStaticBytes(codec, b.StateRoot[:])
-	ssz.DefineDynamicObjectOffset(codec, &b.Body)
-
-	ssz.DefineDynamicObjectContent(codec, &b.Body)
+	Slot          Slot             `json:"slot" ssz-size:"8"`
+	ProposerIndex uint64           `json:"proposer_index" ssz-size:"8"`
+	ParentRoot    Hash             `json:"parent_root" ssz-size:"32"`
+	StateRoot     Hash             `json:"state_root" ssz-size:"32"`
+	Body          *BeaconBlockBody `json:"body"`
 }
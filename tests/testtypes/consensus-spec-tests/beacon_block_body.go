@@ -4,43 +4,15 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type BeaconBlockBody -out gen_beacon_block_body_ssz.go
 
 type BeaconBlockBody struct {
-	RandaoReveal      [96]byte
-	Eth1Data          *Eth1Data
-	Graffiti          [32]byte
-	ProposerSlashings []*ProposerSlashing
-	AttesterSlashings []*AttesterSlashing
-	Attestations      []*Attestation
-	Deposits          []*Deposit
-	VoluntaryExits    []*SignedVoluntaryExit
-}
-
-func (b *BeaconBlockBody) SizeSSZ(fixed bool) uint32 {
-	size := uint32(220)
-	if !fixed {
-		size += ssz.SizeSliceOfStaticObjects(b.ProposerSlashings)
-		size += ssz.SizeSliceOfDynamicObjects(b.AttesterSlashings)
-		size += ssz.SizeSliceOfDynamicObjects(b.Attestations)
-		size += ssz.SizeSliceOfStaticObjects(b.Deposits)
-		size += ssz.SizeSliceOfStaticObjects(b.VoluntaryExits)
-	}
-	return size
-}
-func (b *BeaconBlockBody) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticBytes(codec, b.RandaoReveal[:])
-	ssz.DefineStaticObject(codec, &b.Eth1Data)
-	ssz.DefineStaticBytes(codec, b.Graffiti[:])
-	ssz.DefineSliceOfStaticObjectsOffset(codec, &b.ProposerSlashings)
-	ssz.DefineSliceOfDynamicObjectsOffset(codec, &b.AttesterSlashings)
-	ssz.DefineSliceOfDynamicObjectsOffset(codec, &b.Attestations)
-	ssz.DefineSliceOfStaticObjectsOffset(codec, &b.Deposits)
-	ssz.DefineSliceOfStaticObjectsOffset(codec, &b.VoluntaryExits)
-
-	ssz.DefineSliceOfStaticObjectsContent(codec, &b.ProposerSlashings, 16)
-	ssz.DefineSliceOfDynamicObjectsContent(codec, &b.AttesterSlashings, 2)
-	ssz.DefineSliceOfDynamicObjectsContent(codec, &b.Attestations, 128)
-	ssz.DefineSliceOfStaticObjectsContent(codec, &b.Deposits, 16)
-	ssz.DefineSliceOfStaticObjectsContent(codec, &b.VoluntaryExits, 16)
+	RandaoReveal      [96]byte               `json:"randao_reveal" ssz-size:"96"`
+	Eth1Data          *Eth1Data              `json:"eth1_data"`
+	Graffiti          [32]byte               `json:"graffiti" ssz-size:"32"`
+	ProposerSlashings []*ProposerSlashing    `json:"proposer_slashings" ssz-max:"16"`
+	AttesterSlashings []*AttesterSlashing    `json:"attester_slashings" ssz-max:"2"`
+	Attestations      []*Attestation         `json:"attestations" ssz-max:"128"`
+	Deposits          []*Deposit             `json:"deposits" ssz-max:"16"`
+	VoluntaryExits    []*SignedVoluntaryExit `json:"voluntary_exits" ssz-max:"16"`
 }
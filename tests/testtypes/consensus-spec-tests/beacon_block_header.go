@@ -4,21 +4,12 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type BeaconBlockHeader -out gen_beacon_block_header_ssz.go
 
 type BeaconBlockHeader struct {
-	Slot          uint64
-	ProposerIndex uint64
-	ParentRoot    Hash
-	StateRoot     Hash
-	BodyRoot      Hash
-}
-
-func (b *BeaconBlockHeader) SizeSSZ() uint32 { return 112 }
-func (b *BeaconBlockHeader) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineUint64(codec, &b.Slot)              // Field (0) - Slot          -  8 bytes
-	ssz.DefineUint64(codec, &b.ProposerIndex)     // Field (1) - ProposerIndex -  8 bytes
-	ssz.DefineStaticBytes(codec, b.ParentRoot[:]) // Field (2) - ParentRoot    - 32 bytes
-	ssz.DefineStaticBytes(codec, b.StateRoot[:])  // Field (3) - StateRoot    - 32 bytes
-	ssz.DefineStaticBytes(codec, b.BodyRoot[:])   // Field (4) - BodyRoot    - 32 bytes
+	Slot          uint64 `json:"slot" ssz-size:"8"`
+	ProposerIndex uint64 `json:"proposer_index" ssz-size:"8"`
+	ParentRoot    Hash   `json:"parent_root" ssz-size:"32"`
+	StateRoot     Hash   `json:"state_root" ssz-size:"32"`
+	BodyRoot      Hash   `json:"body_root" ssz-size:"32"`
 }
@@ -4,15 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Checkpoint -out gen_checkpoint_ssz.go
 
 type Checkpoint struct {
-	Epoch uint64
-	Root  Hash
-}
-
-func (c *Checkpoint) SizeSSZ() uint32 { return 40 }
-func (c *Checkpoint) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineUint64(codec, &c.Epoch)       // Field (0) - Epoch -  8 bytes
-	ssz.DefineStaticBytes(codec, c.Root[:]) // Field (1) - Root  - 32 bytes
+	Epoch uint64 `json:"epoch" ssz-size:"8"`
+	Root  Hash   `json:"root" ssz-size:"32"`
 }
@@ -4,15 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Deposit -out gen_deposit_ssz.go
 
 type Deposit struct {
-	Proof [33][32]byte
-	Data  *DepositData
-}
-
-func (d *Deposit) SizeSSZ() uint32 { return 33*32 + 184 }
-func (d *Deposit) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineArrayOfStaticBytes(codec, d.Proof[:]) // Field (0) - Proof - 1056 bytes
-	ssz.DefineStaticObject(codec, &d.Data)          // Field (1) - Data  -  184 bytes
+	Proof [33][32]byte `json:"proof" ssz-size:"1056"`
+	Data  *DepositData `json:"data"`
 }
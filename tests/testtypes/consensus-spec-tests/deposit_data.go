@@ -4,20 +4,12 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type DepositData -out gen_deposit_data_ssz.go
 
 type DepositData struct {
-	Pubkey                [48]byte
-	WithdrawalCredentials [32]byte
-	Amount                uint64
-	Signature             [96]byte
-	Root                  [32]byte
-}
-
-func (d *DepositData) SizeSSZ() uint32 { return 184 }
-func (d *DepositData) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticBytes(codec, d.Pubkey[:])                // Field (0) - Pubkey                - 48 bytes
-	ssz.DefineStaticBytes(codec, d.WithdrawalCredentials[:]) // Field (1) - WithdrawalCredentials - 32 bytes
-	ssz.DefineUint64(codec, &d.Amount)                       // Field (2) - Amount                - 32 bytes
-	ssz.DefineStaticBytes(codec, d.Signature[:])             // Field (3) - Signature             - 32 bytes
+	Pubkey                [48]byte `json:"pubkey" ssz-size:"48"`
+	WithdrawalCredentials [32]byte `json:"withdrawal_credentials" ssz-size:"32"`
+	Amount                uint64   `json:"amount" ssz-size:"8"`
+	Signature             [96]byte `json:"signature" ssz-size:"96"`
+	Root                  [32]byte `ssz:"-"`
 }
@@ -4,17 +4,10 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Eth1Data -out gen_eth1_data_ssz.go
 
 type Eth1Data struct {
-	DepositRoot  Hash
-	DepositCount uint64
-	BlockHash    Hash
-}
-
-func (d *Eth1Data) SizeSSZ() uint32 { return 72 }
-func (d *Eth1Data) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticBytes(codec, d.DepositRoot[:]) // Field (0) - DepositRoot  - 32 bytes
-	ssz.DefineUint64(codec, &d.DepositCount)       // Field (1) - DepositCount -  8 bytes
-	ssz.DefineStaticBytes(codec, d.BlockHash[:])   // Field (0) - BlockHash    - 32 bytes
+	DepositRoot  Hash   `json:"deposit_root" ssz-size:"32"`
+	DepositCount uint64 `json:"deposit_count" ssz-size:"8"`
+	BlockHash    Hash   `json:"block_hash" ssz-size:"32"`
 }
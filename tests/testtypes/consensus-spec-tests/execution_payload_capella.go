@@ -4,56 +4,24 @@
 
 package consensus_spec_tests
 
-import (
-	"github.com/holiman/uint256"
-	"github.com/karalabe/ssz"
-)
+import "github.com/holiman/uint256"
 
-type ExecutionPayloadCapella struct {
-	ParentHash    Hash
-	FeeRecipient  Address
-	StateRoot     Hash
-	ReceiptsRoot  Hash
-	LogsBloom     LogsBloom
-	PrevRandao    Hash
-	BlockNumber   uint64
-	GasLimit      uint64
-	GasUsed       uint64
-	Timestamp     uint64
-	ExtraData     []byte
-	BaseFeePerGas *uint256.Int
-	BlockHash     Hash
-	Transactions  [][]byte
-	Withdrawals   []*Withdrawal
-}
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type ExecutionPayloadCapella -out gen_execution_payload_capella_ssz.go
 
-func (e *ExecutionPayloadCapella) SizeSSZ(fixed bool) uint32 {
-	size := uint32(512)
-	if !fixed {
-		size += ssz.SizeDynamicBytes(e.ExtraData)           // Field (10) - ExtraData    - max 32 bytes (not enforced)
-		size += ssz.SizeSliceOfDynamicBytes(e.Transactions) // Field (13) - Transactions - max 1048576 items, 1073741824 bytes each (not enforced)
-		size += ssz.SizeSliceOfStaticObjects(e.Withdrawals) // Field (14) - Withdrawals  - max 16 items, 44 bytes each (not enforced)
-	}
-	return size
-}
-func (e *ExecutionPayloadCapella) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticBytes(codec, e.ParentHash[:])               // Field  ( 0) - ParentHash    -  32 bytes
-	ssz.DefineStaticBytes(codec, e.FeeRecipient[:])             // Field  ( 1) - FeeRecipient  -  20 bytes
-	ssz.DefineStaticBytes(codec, e.StateRoot[:])                // Field  ( 2) - StateRoot     -  32 bytes
-	ssz.DefineStaticBytes(codec, e.ReceiptsRoot[:])             // Field  ( 3) - ReceiptsRoot  -  32 bytes
-	ssz.DefineStaticBytes(codec, e.LogsBloom[:])                // Field  ( 4) - LogsBloom     - 256 bytes
-	ssz.DefineStaticBytes(codec, e.PrevRandao[:])               // Field  ( 5) - PrevRandao    -  32 bytes
-	ssz.DefineUint64(codec, &e.BlockNumber)                     // Field  ( 6) - BlockNumber   -   8 bytes
-	ssz.DefineUint64(codec, &e.GasLimit)                        // Field  ( 7) - GasLimit      -   8 bytes
-	ssz.DefineUint64(codec, &e.GasUsed)                         // Field  ( 8) - GasUsed       -   8 bytes
-	ssz.DefineUint64(codec, &e.Timestamp)                       // Field  ( 9) - Timestamp     -   8 bytes
-	ssz.DefineDynamicBytesOffset(codec, &e.ExtraData)           // Offset (10) - ExtraData     -   4 bytes
-	ssz.DefineUint256(codec, &e.BaseFeePerGas)                  // Field  (11) - BaseFeePerGas -  32 bytes
-	ssz.DefineStaticBytes(codec, e.BlockHash[:])                // Field  (12) - BlockHash     -  32 bytes
-	ssz.DefineSliceOfDynamicBytesOffset(codec, &e.Transactions) // Offset (13) - Transactions  -   4 bytes
-	ssz.DefineSliceOfStaticObjectsOffset(codec, &e.Withdrawals) // Offset (14) - Withdrawals - 4 bytes
-
-	ssz.DefineDynamicBytesContent(codec, &e.ExtraData, 32)                                 // Content (10) - ExtraData     -   4 bytes
-	ssz.DefineSliceOfDynamicBytesContent(codec, &e.Transactions, 1_048_576, 1_073_741_824) // Content (13) - Transactions  -   4 bytes
-	ssz.DefineSliceOfStaticObjectsContent(codec, &e.Withdrawals, 16)                       // Content (14) - Withdrawals - 4 bytes
+type ExecutionPayloadCapella struct {
+	ParentHash    Hash          `json:"parent_hash" ssz-size:"32"`
+	FeeRecipient  Address       `json:"fee_recipient" ssz-size:"20"`
+	StateRoot     Hash          `json:"state_root" ssz-size:"32"`
+	ReceiptsRoot  Hash          `json:"receipts_root" ssz-size:"32"`
+	LogsBloom     LogsBloom     `json:"logs_bloom" ssz-size:"256"`
+	PrevRandao    Hash          `json:"prev_randao" ssz-size:"32"`
+	BlockNumber   uint64        `json:"block_number" ssz-size:"8"`
+	GasLimit      uint64        `json:"gas_limit" ssz-size:"8"`
+	GasUsed       uint64        `json:"gas_used" ssz-size:"8"`
+	Timestamp     uint64        `json:"timestamp" ssz-size:"8"`
+	ExtraData     []byte        `json:"extra_data" ssz-max:"32"`
+	BaseFeePerGas *uint256.Int  `json:"base_fee_per_gas" ssz-size:"32"`
+	BlockHash     Hash          `json:"block_hash" ssz-size:"32"`
+	Transactions  [][]byte      `json:"transactions" ssz-max:"1048576,1073741824"`
+	Withdrawals   []*Withdrawal `json:"withdrawals" ssz-max:"16"`
 }
@@ -0,0 +1,37 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (b *BeaconBlockBody) SizeSSZ(fixed bool) uint32 {
+	size := uint32(220)
+	if !fixed {
+		size += ssz.SizeSliceOfStaticObjects(b.ProposerSlashings)  // Field (3) - ProposerSlashings - max 16 items, 416 bytes each (not enforced)
+		size += ssz.SizeSliceOfDynamicObjects(b.AttesterSlashings) // Field (4) - AttesterSlashings - max 2 items (not enforced)
+		size += ssz.SizeSliceOfDynamicObjects(b.Attestations)      // Field (5) - Attestations      - max 128 items (not enforced)
+		size += ssz.SizeSliceOfStaticObjects(b.Deposits)           // Field (6) - Deposits          - max 16 items, 1240 bytes each (not enforced)
+		size += ssz.SizeSliceOfStaticObjects(b.VoluntaryExits)     // Field (7) - VoluntaryExits    - max 16 items, 112 bytes each (not enforced)
+	}
+	return size
+}
+func (b *BeaconBlockBody) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStaticBytes(codec.Named("randao_reveal"), b.RandaoReveal[:])                         // Field  (0) - RandaoReveal      - 96 bytes
+	ssz.DefineStaticObject(codec.Named("eth1_data"), &b.Eth1Data)                                  // Field  (1) - Eth1Data          - 72 bytes
+	ssz.DefineStaticBytes(codec.Named("graffiti"), b.Graffiti[:])                                  // Field  (2) - Graffiti          - 32 bytes
+	ssz.DefineSliceOfStaticObjectsOffset(codec.Named("proposer_slashings"), &b.ProposerSlashings)  // Offset (3) - ProposerSlashings -  4 bytes
+	ssz.DefineSliceOfDynamicObjectsOffset(codec.Named("attester_slashings"), &b.AttesterSlashings) // Offset (4) - AttesterSlashings -  4 bytes
+	ssz.DefineSliceOfDynamicObjectsOffset(codec.Named("attestations"), &b.Attestations)            // Offset (5) - Attestations      -  4 bytes
+	ssz.DefineSliceOfStaticObjectsOffset(codec.Named("deposits"), &b.Deposits)                     // Offset (6) - Deposits          -  4 bytes
+	ssz.DefineSliceOfStaticObjectsOffset(codec.Named("voluntary_exits"), &b.VoluntaryExits)        // Offset (7) - VoluntaryExits    -  4 bytes
+
+	ssz.DefineSliceOfStaticObjectsContent(codec, &b.ProposerSlashings, 16) // Offset (3) - ProposerSlashings -  4 bytes
+	ssz.DefineSliceOfDynamicObjectsContent(codec, &b.AttesterSlashings, 2) // Offset (4) - AttesterSlashings -  4 bytes
+	ssz.DefineSliceOfDynamicObjectsContent(codec, &b.Attestations, 128)    // Offset (5) - Attestations      -  4 bytes
+	ssz.DefineSliceOfStaticObjectsContent(codec, &b.Deposits, 16)          // Offset (6) - Deposits          -  4 bytes
+	ssz.DefineSliceOfStaticObjectsContent(codec, &b.VoluntaryExits, 16)    // Offset (7) - VoluntaryExits    -  4 bytes
+}
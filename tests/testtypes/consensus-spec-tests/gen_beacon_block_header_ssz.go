@@ -0,0 +1,18 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (b *BeaconBlockHeader) SizeSSZ() uint32 { return 112 }
+func (b *BeaconBlockHeader) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("slot"), &b.Slot)                     // Field (0) - Slot          -  8 bytes
+	ssz.DefineUint64(codec.Named("proposer_index"), &b.ProposerIndex)  // Field (1) - ProposerIndex -  8 bytes
+	ssz.DefineStaticBytes(codec.Named("parent_root"), b.ParentRoot[:]) // Field (2) - ParentRoot    - 32 bytes
+	ssz.DefineStaticBytes(codec.Named("state_root"), b.StateRoot[:])   // Field (3) - StateRoot     - 32 bytes
+	ssz.DefineStaticBytes(codec.Named("body_root"), b.BodyRoot[:])     // Field (4) - BodyRoot      - 32 bytes
+}
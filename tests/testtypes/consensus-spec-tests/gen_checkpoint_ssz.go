@@ -0,0 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (c *Checkpoint) SizeSSZ() uint32 { return 40 }
+func (c *Checkpoint) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("epoch"), &c.Epoch)      // Field (0) - Epoch -  8 bytes
+	ssz.DefineStaticBytes(codec.Named("root"), c.Root[:]) // Field (1) - Root  - 32 bytes
+}
@@ -0,0 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (d *Deposit) SizeSSZ() uint32 { return 33*32 + 184 }
+func (d *Deposit) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineArrayOfStaticBytes(codec.Named("proof"), d.Proof[:]) // Field (0) - Proof - 1056 bytes
+	ssz.DefineStaticObject(codec.Named("data"), &d.Data)           // Field (1) - Data  -  184 bytes
+}
@@ -0,0 +1,37 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (e *ExecutionPayload) SizeSSZ(fixed bool) uint32 {
+	size := uint32(508)
+	if !fixed {
+		size += ssz.SizeDynamicBytes(e.ExtraData)           // Field (10) - ExtraData    - max 32 bytes (not enforced)
+		size += ssz.SizeSliceOfDynamicBytes(e.Transactions) // Field (13) - Transactions - max 1048576 items, 1073741824 bytes each (not enforced)
+	}
+	return size
+}
+func (e *ExecutionPayload) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStaticBytes(codec.Named("parent_hash"), e.ParentHash[:])                // Field  ( 0) - ParentHash    -  32 bytes
+	ssz.DefineStaticBytes(codec.Named("fee_recipient"), e.FeeRecipient[:])            // Field  ( 1) - FeeRecipient  -  20 bytes
+	ssz.DefineStaticBytes(codec.Named("state_root"), e.StateRoot[:])                  // Field  ( 2) - StateRoot     -  32 bytes
+	ssz.DefineStaticBytes(codec.Named("receipts_root"), e.ReceiptsRoot[:])            // Field  ( 3) - ReceiptsRoot  -  32 bytes
+	ssz.DefineStaticBytes(codec.Named("logs_bloom"), e.LogsBloom[:])                  // Field  ( 4) - LogsBloom     - 256 bytes
+	ssz.DefineStaticBytes(codec.Named("prev_randao"), e.PrevRandao[:])                // Field  ( 5) - PrevRandao    -  32 bytes
+	ssz.DefineUint64(codec.Named("block_number"), &e.BlockNumber)                     // Field  ( 6) - BlockNumber   -   8 bytes
+	ssz.DefineUint64(codec.Named("gas_limit"), &e.GasLimit)                           // Field  ( 7) - GasLimit      -   8 bytes
+	ssz.DefineUint64(codec.Named("gas_used"), &e.GasUsed)                             // Field  ( 8) - GasUsed       -   8 bytes
+	ssz.DefineUint64(codec.Named("timestamp"), &e.Timestamp)                          // Field  ( 9) - Timestamp     -   8 bytes
+	ssz.DefineDynamicBytesOffset(codec.Named("extra_data"), &e.ExtraData)             // Offset (10) - ExtraData     -   4 bytes
+	ssz.DefineUint256(codec.Named("base_fee_per_gas"), &e.BaseFeePerGas)              // Field  (11) - BaseFeePerGas -  32 bytes
+	ssz.DefineStaticBytes(codec.Named("block_hash"), e.BlockHash[:])                  // Field  (12) - BlockHash     -  32 bytes
+	ssz.DefineSliceOfDynamicBytesOffset(codec.Named("transactions"), &e.Transactions) // Offset (13) - Transactions  -   4 bytes
+
+	ssz.DefineDynamicBytesContent(codec, &e.ExtraData, 32)                                 // Offset (10) - ExtraData     -   4 bytes
+	ssz.DefineSliceOfDynamicBytesContent(codec, &e.Transactions, 1_048_576, 1_073_741_824) // Offset (13) - Transactions  -   4 bytes
+}
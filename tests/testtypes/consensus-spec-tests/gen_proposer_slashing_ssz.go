@@ -0,0 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (s *ProposerSlashing) SizeSSZ() uint32 { return 416 }
+func (s *ProposerSlashing) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStaticObject(codec.Named("signed_header_1"), &s.Header1) // Field (0) - Header1 - 208 bytes
+	ssz.DefineStaticObject(codec.Named("signed_header_2"), &s.Header2) // Field (1) - Header2 - 208 bytes
+}
@@ -0,0 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (s *SignedBeaconBlockHeader) SizeSSZ() uint32 { return 208 }
+func (s *SignedBeaconBlockHeader) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStaticObject(codec.Named("message"), &s.Header)       // Field (0) - Header    - 112 bytes
+	ssz.DefineStaticBytes(codec.Named("signature"), s.Signature[:]) // Field (1) - Signature -  96 bytes
+}
@@ -0,0 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (v *SignedVoluntaryExit) SizeSSZ() uint32 { return 112 }
+func (v *SignedVoluntaryExit) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStaticObject(codec.Named("message"), &v.Exit)         // Field (0) - Exit      - 16 bytes
+	ssz.DefineStaticBytes(codec.Named("signature"), v.Signature[:]) // Field (1) - Signature - 96 bytes
+}
@@ -0,0 +1,21 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (v *Validator) SizeSSZ() uint32 { return 121 }
+func (v *Validator) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineStaticBytes(codec.Named("pubkey"), v.Pubkey[:])                                    // Field (0) - Pubkey                     - 48 bytes
+	ssz.DefineStaticBytes(codec.Named("withdrawal_credentials"), v.WithdrawalCredentials[:])     // Field (1) - WithdrawalCredentials      - 32 bytes
+	ssz.DefineUint64(codec.Named("effective_balance"), &v.EffectiveBalance)                      // Field (2) - EffectiveBalance           -  8 bytes
+	ssz.DefineBool(codec.Named("slashed"), &v.Slashed)                                           // Field (3) - Slashed                    -  1 byte
+	ssz.DefineUint64(codec.Named("activation_eligibility_epoch"), &v.ActivationEligibilityEpoch) // Field (4) - ActivationEligibilityEpoch -  8 bytes
+	ssz.DefineUint64(codec.Named("activation_epoch"), &v.ActivationEpoch)                        // Field (5) - ActivationEpoch            -  8 bytes
+	ssz.DefineUint64(codec.Named("exit_epoch"), &v.ExitEpoch)                                    // Field (6) - ExitEpoch                  -  8 bytes
+	ssz.DefineUint64(codec.Named("withdrawable_epoch"), &v.WithdrawableEpoch)                    // Field (7) - WithdrawableEpoch          -  8 bytes
+}
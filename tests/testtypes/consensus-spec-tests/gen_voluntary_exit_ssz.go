@@ -0,0 +1,15 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Code generated by github.com/karalabe/ssz/cmd/sszgen. DO NOT EDIT.
+
+package consensus_spec_tests
+
+import "github.com/karalabe/ssz"
+
+func (v *VoluntaryExit) SizeSSZ() uint32 { return 16 }
+func (v *VoluntaryExit) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint64(codec.Named("epoch"), &v.Epoch)                    // Field (0) - Epoch          - 8 bytes
+	ssz.DefineUint64(codec.Named("validator_index"), &v.ValidatorIndex) // Field (1) - ValidatorIndex - 8 bytes
+}
@@ -23,4 +23,8 @@ func (h *HistoricalBatch) DefineSSZ(codec *ssz.Codec) {
 		ssz.DecodeArrayOfStaticBytes(dec, h.BlockRoots[:])
 		ssz.DecodeArrayOfStaticBytes(dec, h.StateRoots[:])
 	})
+	codec.DefineHasher(func(has *ssz.Hasher) {
+		ssz.HashArrayOfStaticBytes(has, h.BlockRoots[:])
+		ssz.HashArrayOfStaticBytes(has, h.StateRoots[:])
+	})
 }
@@ -4,25 +4,10 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type IndexedAttestation -out gen_indexed_attestation_ssz.go
 
 type IndexedAttestation struct {
-	AttestationIndices []uint64
-	Data               *AttestationData
-	Signature          [96]byte
-}
-
-func (a *IndexedAttestation) SizeSSZ(fixed bool) uint32 {
-	size := uint32(228)
-	if !fixed {
-		size += ssz.SizeSliceOfUint64s(a.AttestationIndices)
-	}
-	return size
-}
-func (a *IndexedAttestation) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineSliceOfUint64sOffset(codec, &a.AttestationIndices) // Offset (0) - AttestationIndices - 4 bytes
-	ssz.DefineStaticObject(codec, &a.Data)                       // Field (1) - Data      - 128 bytes
-	ssz.DefineStaticBytes(codec, a.Signature[:])                 // Field (2) - Signature - 96 bytes
-
-	ssz.DefineSliceOfUint64sContent(codec, &a.AttestationIndices, 2048) // Offset (0) - AttestationIndices - 4 bytes
+	AttestationIndices []uint64         `json:"attesting_indices" ssz-max:"2048"`
+	Data               *AttestationData `json:"data"`
+	Signature          [96]byte         `json:"signature" ssz-size:"96"`
 }
@@ -4,15 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type ProposerSlashing -out gen_proposer_slashing_ssz.go
 
 type ProposerSlashing struct {
-	Header1 *SignedBeaconBlockHeader
-	Header2 *SignedBeaconBlockHeader
-}
-
-func (s *ProposerSlashing) SizeSSZ() uint32 { return 416 }
-func (s *ProposerSlashing) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticObject(codec, &s.Header1) // Field (0) - Header1 - 208 bytes
-	ssz.DefineStaticObject(codec, &s.Header2) // Field (1) - Header2 - 208 bytes
+	Header1 *SignedBeaconBlockHeader `json:"signed_header_1"`
+	Header2 *SignedBeaconBlockHeader `json:"signed_header_2"`
 }
@@ -4,15 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type SignedBeaconBlockHeader -out gen_signed_beacon_block_header_ssz.go
 
 type SignedBeaconBlockHeader struct {
-	Header    *BeaconBlockHeader
-	Signature [96]byte
-}
-
-func (s *SignedBeaconBlockHeader) SizeSSZ() uint32 { return 208 }
-func (s *SignedBeaconBlockHeader) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticObject(codec, &s.Header)     // Field (0) - Header    - 112 bytes
-	ssz.DefineStaticBytes(codec, s.Signature[:]) // Field (1) - Signature -  96 bytes
+	Header    *BeaconBlockHeader `json:"message"`
+	Signature [96]byte           `json:"signature" ssz-size:"96"`
 }
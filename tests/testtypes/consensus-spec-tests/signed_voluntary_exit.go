@@ -4,15 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type SignedVoluntaryExit -out gen_signed_voluntary_exit_ssz.go
 
 type SignedVoluntaryExit struct {
 	Exit      *VoluntaryExit `json:"message"`
 	Signature [96]byte       `json:"signature" ssz-size:"96"`
 }
-
-func (v *SignedVoluntaryExit) SizeSSZ() uint32 { return 112 }
-func (v *SignedVoluntaryExit) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineStaticObject(codec, &v.Exit)       // Field (0) - Exit          - 16 bytes
-	ssz.DefineStaticBytes(codec, v.Signature[:]) // Field (1) - Signature - 96 bytes
-}
@@ -0,0 +1,12 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type SyncAggregate -out gen_sync_aggregate_ssz.go
+
+type SyncAggregate struct {
+	SyncCommitteeBits      [64]byte `json:"sync_committee_bits" ssz:"bits" ssz-size:"512"`
+	SyncCommitteeSignature [96]byte `json:"sync_committee_signature" ssz-size:"96"`
+}
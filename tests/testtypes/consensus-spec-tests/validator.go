@@ -0,0 +1,18 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package consensus_spec_tests
+
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Validator -out gen_validator_ssz.go
+
+type Validator struct {
+	Pubkey                     [48]byte `json:"pubkey" ssz-size:"48"`
+	WithdrawalCredentials      [32]byte `json:"withdrawal_credentials" ssz-size:"32"`
+	EffectiveBalance           uint64   `json:"effective_balance" ssz-size:"8"`
+	Slashed                    bool     `json:"slashed" ssz-size:"1"`
+	ActivationEligibilityEpoch uint64   `json:"activation_eligibility_epoch" ssz-size:"8"`
+	ActivationEpoch            uint64   `json:"activation_epoch" ssz-size:"8"`
+	ExitEpoch                  uint64   `json:"exit_epoch" ssz-size:"8"`
+	WithdrawableEpoch          uint64   `json:"withdrawable_epoch" ssz-size:"8"`
+}
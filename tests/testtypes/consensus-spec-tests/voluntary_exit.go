@@ -4,15 +4,9 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type VoluntaryExit -out gen_voluntary_exit_ssz.go
 
 type VoluntaryExit struct {
-	Epoch          uint64
-	ValidatorIndex uint64
-}
-
-func (v *VoluntaryExit) SizeSSZ() uint32 { return 16 }
-func (v *VoluntaryExit) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineUint64(codec, &v.Epoch)          // Field (0) - Epoch          - 8 bytes
-	ssz.DefineUint64(codec, &v.ValidatorIndex) // Field (1) - ValidatorIndex - 8 bytes
+	Epoch          uint64 `json:"epoch" ssz-size:"8"`
+	ValidatorIndex uint64 `json:"validator_index" ssz-size:"8"`
 }
@@ -4,19 +4,11 @@
 
 package consensus_spec_tests
 
-import "github.com/karalabe/ssz"
+//go:generate go run github.com/karalabe/ssz/cmd/sszgen -type Withdrawal -out gen_withdrawal_ssz.go
 
 type Withdrawal struct {
-	Index     uint64  `ssz-size:"8"`
-	Validator uint64  `ssz-size:"8"`
-	Address   Address `ssz-size:"20"`
-	Amount    uint64  `ssz-size:"8"`
-}
-
-func (w *Withdrawal) SizeSSZ() uint32 { return 44 }
-func (w *Withdrawal) DefineSSZ(codec *ssz.Codec) {
-	ssz.DefineUint64(codec, &w.Index)          // Field (0) - Index          -  8 bytes
-	ssz.DefineUint64(codec, &w.Validator)      // Field (1) - ValidatorIndex -  8 bytes
-	ssz.DefineStaticBytes(codec, w.Address[:]) // Field (2) - Address        - 20 bytes
-	ssz.DefineUint64(codec, &w.Amount)         // Field (3) - Amount         -  8 bytes
+	Index     uint64  `json:"index" ssz-size:"8"`
+	Validator uint64  `json:"validator_index" ssz-size:"8"`
+	Address   Address `json:"address" ssz-size:"20"`
+	Amount    uint64  `json:"amount" ssz-size:"8"`
 }
@@ -0,0 +1,131 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// Tests that decoding truncated byte buffers fails gracefully instead of going
+// out of bounds.
+func TestTruncated(t *testing.T) {
+	testTruncated[*types.Attestation](t)
+	testTruncated[*types.AttestationData](t)
+	testTruncated[*types.AttesterSlashing](t)
+	testTruncated[*types.BeaconBlock](t)
+	testTruncated[*types.BeaconBlockBody](t)
+	testTruncated[*types.BeaconBlockHeader](t)
+	testTruncated[*types.Checkpoint](t)
+	testTruncated[*types.Deposit](t)
+	testTruncated[*types.DepositData](t)
+	testTruncated[*types.Eth1Data](t)
+	testTruncated[*types.ExecutionPayload](t)
+	testTruncated[*types.ExecutionPayloadCapella](t)
+	testTruncated[*types.HistoricalBatch](t)
+	testTruncated[*types.IndexedAttestation](t)
+	testTruncated[*types.ProposerSlashing](t)
+	testTruncated[*types.SignedBeaconBlockHeader](t)
+	testTruncated[*types.SignedVoluntaryExit](t)
+	testTruncated[*types.SyncAggregate](t)
+	testTruncated[*types.Validator](t)
+	testTruncated[*types.VoluntaryExit](t)
+	testTruncated[*types.Withdrawal](t)
+	testTruncated[*testArrays](t)
+	testTruncated[*testBinaries](t)
+	testTruncated[*testBools](t)
+	testTruncated[*testUints](t)
+}
+
+func testTruncated[T newableObject[U], U any](t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 4; i++ {
+		obj := T(new(U))
+		fillRandom(rng, reflect.ValueOf(obj).Elem(), "")
+
+		blob := make([]byte, ssz.Size(obj))
+		if err := ssz.EncodeToBytes(blob, obj); err != nil {
+			t.Fatalf("%T: failed to encode: %v", obj, err)
+		}
+		// Dynamic objects might legitimately decode with their last item shorter,
+		// but static ones must report the missing bytes
+		_, static := any(obj).(ssz.StaticObject)
+		for j := 0; j < min(len(blob), 256); j++ {
+			n := j
+			if len(blob) > 256 {
+				n = rng.Intn(len(blob))
+			}
+			err := ssz.DecodeFromBytes(blob[:n], T(new(U)))
+			if !static {
+				continue
+			}
+			var short *ssz.ShortBufferError
+			if !errors.As(err, &short) || !errors.Is(err, ssz.ErrShortBuffer) {
+				t.Fatalf("%T: truncated to %d bytes: unexpected error: %v", obj, n, err)
+			}
+			if short.Needed <= short.Available {
+				t.Fatalf("%T: truncated to %d bytes: invalid counts: %v", obj, n, err)
+			}
+		}
+	}
+}
+
+// Tests that decoding inputs with trailing bytes fails instead of silently
+// ignoring the extra data.
+func TestTrailing(t *testing.T) {
+	testTrailing[*types.Attestation](t)
+	testTrailing[*types.AttestationData](t)
+	testTrailing[*types.BeaconBlockHeader](t)
+	testTrailing[*types.Checkpoint](t)
+	testTrailing[*types.DepositData](t)
+	testTrailing[*types.ExecutionPayload](t)
+	testTrailing[*types.Validator](t)
+	testTrailing[*types.Withdrawal](t)
+	testTrailing[*testArrays](t)
+	testTrailing[*testBinaries](t)
+	testTrailing[*testUints](t)
+}
+
+func testTrailing[T newableObject[U], U any](t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 4; i++ {
+		obj := T(new(U))
+		fillRandom(rng, reflect.ValueOf(obj).Elem(), "")
+
+		blob := make([]byte, ssz.Size(obj))
+		if err := ssz.EncodeToBytes(blob, obj); err != nil {
+			t.Fatalf("%T: failed to encode: %v", obj, err)
+		}
+		if err := ssz.DecodeFromStream(bytes.NewReader(blob), T(new(U)), uint32(len(blob))); err != nil {
+			t.Fatalf("%T: failed to decode exact stream: %v", obj, err)
+		}
+		// Dynamic objects absorb the extra bytes into their last item, but static
+		// ones must report them
+		padded := append(blob, make([]byte, 10)...)
+		rng.Read(padded[len(blob):])
+
+		errBytes := ssz.DecodeFromBytes(padded, T(new(U)))
+		errStream := ssz.DecodeFromStream(bytes.NewReader(padded), T(new(U)), uint32(len(padded)))
+		if _, static := any(obj).(ssz.StaticObject); !static {
+			continue
+		}
+		if !errors.Is(errBytes, ssz.ErrTrailingBytes) {
+			t.Fatalf("%T: buffer with trailing bytes: unexpected error: %v", obj, errBytes)
+		}
+		if !errors.Is(errStream, ssz.ErrTrailingBytes) {
+			t.Fatalf("%T: stream with trailing bytes: unexpected error: %v", obj, errStream)
+		}
+		// Streams declared shorter than the object must not be read past
+		if err := ssz.DecodeFromStream(bytes.NewReader(padded), T(new(U)), uint32(len(blob)-1)); err == nil {
+			t.Fatalf("%T: stream with short size: decoded", obj)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/karalabe/ssz"
+)
+
+// testUints is a container with all the smaller-than-uint64 and the uint128
+// integer field types.
+type testUints struct {
+	A uint8
+	B uint16
+	C uint32
+	D [2]uint64
+}
+
+func (t *testUints) SizeSSZ() uint32 { return 1 + 2 + 4 + 16 }
+func (t *testUints) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUint8(codec, &t.A)   // Field (0) - A -  1 byte
+	ssz.DefineUint16(codec, &t.B)  // Field (1) - B -  2 bytes
+	ssz.DefineUint32(codec, &t.C)  // Field (2) - C -  4 bytes
+	ssz.DefineUint128(codec, &t.D) // Field (3) - D - 16 bytes
+}
+
+// Tests that the various sized integers can be encoded, decoded and hashed in
+// both streaming and buffered mode.
+func TestUints(t *testing.T) {
+	obj := &testUints{A: 0x12, B: 0x3456, C: 0x789abcde, D: [2]uint64{0x090a0b0c0d0e0f10, 0x0102030405060708}}
+	want, _ := hex.DecodeString("125634debc9a78100f0e0d0c0b0a090807060504030201")
+
+	testRoundTrip(t, obj, want, "9850a1a8e52b9e4db62a83a487712bd7b1f79897f383c400101ba04638edb61a")
+}
@@ -0,0 +1,124 @@
+// ssz: Go Simple Serialize (SSZ) codec library
+// Copyright 2024 ssz Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/karalabe/ssz"
+	types "github.com/karalabe/ssz/tests/testtypes/consensus-spec-tests"
+)
+
+// testUnion is a container with a Union[None, Checkpoint, Attestation] field.
+type testUnion struct {
+	Selector uint8
+	Value    ssz.Object
+}
+
+func (t *testUnion) SizeSSZ(fixed bool) uint32 {
+	size := uint32(4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeUnion(t.Value)
+	return size
+}
+func (t *testUnion) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUnionOffset(codec, &t.Selector, &t.Value) // Offset (0) - Value - 4 bytes
+
+	ssz.DefineUnionContent(codec, &t.Selector, &t.Value, nil, // Field (0) - Value - ? bytes
+		func() ssz.Object { return new(types.Checkpoint) },
+		func() ssz.Object { return new(types.Attestation) },
+	)
+}
+
+// Tests that unions can be encoded, decoded and hashed with all their variants.
+func TestUnion(t *testing.T) {
+	tests := []struct {
+		obj  *testUnion
+		root string
+	}{
+		{&testUnion{}, "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b"},
+		{&testUnion{Selector: 1, Value: &types.Checkpoint{Epoch: 1, Root: types.Hash{0x02}}}, "4f4b6dcd74ac8bc90aace630167373851ac4710025ab2db5ae02c6b2cce1ab85"},
+		{&testUnion{Selector: 2, Value: &types.Attestation{
+			AggregationBits: []byte{0x03, 0x01},
+			Data:            &types.AttestationData{Slot: 4, Source: new(types.Checkpoint), Target: new(types.Checkpoint)},
+		}}, "048033ce6d2140220d669e3811753a8b7aeeac8df86c127d1a4d5512f33e7bff"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			testRoundTrip(t, tt.obj, nil, tt.root)
+		})
+	}
+}
+
+// testUnionLateNone is a container with a union illegally defining a None variant
+// at a non-zero selector.
+type testUnionLateNone struct {
+	Selector uint8
+	Value    ssz.Object
+}
+
+func (t *testUnionLateNone) SizeSSZ(fixed bool) uint32 {
+	size := uint32(4)
+	if fixed {
+		return size
+	}
+	size += ssz.SizeUnion(t.Value)
+	return size
+}
+func (t *testUnionLateNone) DefineSSZ(codec *ssz.Codec) {
+	ssz.DefineUnionOffset(codec, &t.Selector, &t.Value) // Offset (0) - Value - 4 bytes
+
+	ssz.DefineUnionContent(codec, &t.Selector, &t.Value, func() ssz.Object { return new(types.Checkpoint) }, nil) // Field (0) - Value - ? bytes
+}
+
+// Tests that unions with invalid selectors or mismatching value sizes are rejected.
+func TestUnionInvalid(t *testing.T) {
+	tests := []struct {
+		blob []byte
+		err  error
+	}{
+		{[]byte{0x04, 0x00, 0x00, 0x00}, ssz.ErrInvalidUnionSelector},
+		{[]byte{0x04, 0x00, 0x00, 0x00, 0x03}, ssz.ErrInvalidUnionSelector},
+		{[]byte{0x04, 0x00, 0x00, 0x00, 0x00, 0x00}, ssz.ErrUnionSizeMismatch},
+		{append([]byte{0x04, 0x00, 0x00, 0x00, 0x01}, make([]byte, 39)...), ssz.ErrUnionSizeMismatch},
+		{append([]byte{0x04, 0x00, 0x00, 0x00, 0x01}, make([]byte, 41)...), ssz.ErrUnionSizeMismatch},
+	}
+	for i, tt := range tests {
+		if err := ssz.DecodeFromStream(bytes.NewReader(tt.blob), new(testUnion), uint32(len(tt.blob))); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: stream error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err := ssz.DecodeFromBytes(tt.blob, new(testUnion)); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: buffer error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// Ensure None variants are only accepted as the first option
+	blob := []byte{0x04, 0x00, 0x00, 0x00, 0x01}
+	if err := ssz.DecodeFromBytes(blob, new(testUnionLateNone)); !errors.Is(err, ssz.ErrInvalidUnionSelector) {
+		t.Errorf("late None error mismatch: have %v, want %v", err, ssz.ErrInvalidUnionSelector)
+	}
+	// Ensure selectors inconsistent with their values are not encoded either
+	encTests := []struct {
+		obj ssz.Object
+		err error
+	}{
+		{&testUnion{Selector: 1}, ssz.ErrInvalidUnionSelector},
+		{&testUnion{Selector: 3, Value: new(types.Checkpoint)}, ssz.ErrInvalidUnionSelector},
+		{&testUnion{Selector: 0, Value: new(types.Checkpoint)}, ssz.ErrUnionSizeMismatch},
+		{&testUnionLateNone{Selector: 1}, ssz.ErrInvalidUnionSelector},
+	}
+	for i, tt := range encTests {
+		if err := ssz.EncodeToStream(new(bytes.Buffer), tt.obj); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: stream encoding error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err := ssz.EncodeToBytes(make([]byte, ssz.Size(tt.obj)), tt.obj); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: buffer encoding error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}